@@ -16,12 +16,62 @@
 
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+)
 
 type ArchiveCmd struct {
+	ID string `arg:"" help:"ID or pattern of the entry to archive."`
 }
 
 func (cmd *ArchiveCmd) Run() error {
-	fmt.Println("not implemented")
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	store, id, err := resolveID(store, cmd.ID)
+	if err != nil {
+		return err
+	}
+
+	if level := id.Level(); level != jdex.LevelEntry && level != jdex.LevelSub {
+		return fmt.Errorf("%s is not an entry, only entries can be archived", id.String())
+	}
+
+	archive, err := archivePath(store)
+	if err != nil {
+		return err
+	}
+
+	err = store.ScanNoteLinks()
+	if err != nil {
+		return err
+	}
+
+	var dangling []jdex.Link
+	err = recordChange(store, func() (err error) {
+		dangling, err = store.ArchiveEntry(id, archive)
+		return
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Archived %s to \"%s\".\n", id.String(), archive)
+	printDanglingLinks(dangling)
 	return nil
 }
+
+// Get where to archive entries to: the profile's archive, or one kept
+// alongside the system index.
+func archivePath(store *jdfs.Store) (string, error) {
+	if profile.Archive != "" {
+		return profile.Archive, nil
+	}
+
+	return store.SystemFilePath(jdfs.ArchiveFilename)
+}
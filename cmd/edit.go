@@ -23,43 +23,42 @@ import (
 	"path"
 	"runtime"
 
-	"github.com/itisrazza/rzjd/jdex"
 	"github.com/itisrazza/rzjd/jdfs"
 )
 
 type EditCmd struct {
-	ID   string  `arg:"" help:"ID of the entry to edit"`
+	ID   string  `arg:"" help:"ID of the area, category or entry to edit, or pattern of the entry."`
 	Name *string `arg:"" optional:"" help:"Providing a name will rename the entry."`
 
 	Editor *string `short:"e" type:"path" help:"Path to text editor."`
 }
 
 func (cmd *EditCmd) Run() error {
-	id, err := jdex.ParseACID(cmd.ID)
+	store, err := OpenOrCreateStore()
 	if err != nil {
 		return err
 	}
 
-	// if jdex.IsProtectedACID(id) {
-	// 	return fmt.Errorf("%q is a protected ID", id.String())
-	// }
-
-	store, err := OpenOrCreateStore()
+	store, id, err := resolveID(store, cmd.ID)
 	if err != nil {
 		return err
 	}
 
-	entryPath, err := store.EntryPath(id)
+	// if jdex.IsProtectedACID(id) {
+	// 	return fmt.Errorf("%q is a protected ID", id.String())
+	// }
+
+	dirPath, err := store.Path(id)
 	if err != nil {
 		return err
 	}
 
-	err = os.MkdirAll(entryPath, 0755)
+	err = os.MkdirAll(dirPath, 0755)
 	if err != nil {
 		return err
 	}
 
-	return cmd.openEditor(path.Join(entryPath, jdfs.EntryIndexFilename))
+	return cmd.openEditor(path.Join(dirPath, jdfs.EntryIndexFilename))
 }
 
 func (cmd *EditCmd) openEditor(path string) error {
@@ -91,6 +90,10 @@ func (cmd *EditCmd) editorName() (string, error) {
 		return editorName, nil
 	}
 
+	if profile.Editor != "" {
+		return profile.Editor, nil
+	}
+
 	editorName, ok = os.LookupEnv("EDITOR")
 	if ok {
 		return editorName, nil
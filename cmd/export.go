@@ -0,0 +1,67 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/itisrazza/rzjd/jdex/jdexgraph"
+)
+
+type ExportCmd struct {
+	Graph ExportGraphCmd `cmd:"" help:"Export the system and its links as a graph."`
+}
+
+type ExportGraphCmd struct {
+	Format string `short:"f" enum:"dot,mermaid" default:"dot" help:"Graph format (dot, mermaid)."`
+	Area   string `short:"a" help:"Only include this area, such as 10-19."`
+	Tag    string `short:"t" help:"Only include entries with this tag."`
+	Depth  int    `short:"d" help:"Levels to include: 1 for areas, 2 for categories, 3 for entries."`
+}
+
+var areaArgRegex = regexp.MustCompile(`^([A-Z0-9])(?:0-([A-Z0-9])9)?$`)
+
+func (cmd *ExportGraphCmd) Run() error {
+	options := jdexgraph.Options{
+		Tag:   cmd.Tag,
+		Depth: cmd.Depth,
+	}
+
+	if cmd.Area != "" {
+		matches := areaArgRegex.FindStringSubmatch(cmd.Area)
+		if matches == nil || (matches[2] != "" && matches[1] != matches[2]) {
+			return fmt.Errorf("%q is not an area", cmd.Area)
+		}
+
+		area := matches[1][0]
+		options.Area = &area
+	}
+
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	err = store.ScanNoteLinks()
+	if err != nil {
+		return err
+	}
+
+	return jdexgraph.Write(store.Index, os.Stdout, cmd.Format, options)
+}
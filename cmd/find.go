@@ -0,0 +1,78 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/itisrazza/rzjd/jdex/jdexquery"
+)
+
+type FindCmd struct {
+	Query string `arg:"" help:"Query to match entries against, such as 'area:10 #tax Status=open modified:this-year'."`
+
+	Sort  string `default:"id" help:"Comma-separated fields to sort by, such as -modified,name. Fields starting with - sort in descending order."`
+	Limit int    `short:"n" help:"Only show this many entries."`
+	Local bool   `help:"Only search the current store, not other registered systems."`
+}
+
+func (cmd *FindCmd) Run() error {
+	query, err := jdexquery.Parse(cmd.Query)
+	if err != nil {
+		return err
+	}
+
+	keys, err := jdexquery.ParseSort(cmd.Sort)
+	if err != nil {
+		return err
+	}
+
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	results := query.Find(store.Index)
+
+	if !cmd.Local {
+		registry, err := openRegistry(store)
+		if err != nil {
+			return err
+		}
+
+		for _, code := range registry.Codes() {
+			system, err := registry.Store(code)
+			if err != nil {
+				return err
+			}
+
+			if system == store {
+				continue
+			}
+
+			for _, result := range query.Find(system.Index) {
+				result.Entry.ID.System = code
+				results = append(results, result)
+			}
+		}
+	}
+
+	jdexquery.SortResults(results, keys)
+	for _, result := range jdexquery.Limit(results, cmd.Limit) {
+		printEntryLine(result.Entry)
+	}
+
+	return nil
+}
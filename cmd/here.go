@@ -0,0 +1,106 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/itisrazza/rzjd/jdfs"
+)
+
+type HereCmd struct {
+	JSON bool `help:"Print the location as JSON."`
+}
+
+type WhichCmd struct {
+	Path string `arg:"" type:"path" help:"Path to locate."`
+	JSON bool   `help:"Print the location as JSON."`
+}
+
+// Location of a path in the store, as printed with --json.
+type location struct {
+	ID    string `json:"id"`
+	Level string `json:"level"`
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Store string `json:"store"`
+}
+
+func (cmd *HereCmd) Run() error {
+	return printLocation(".", cmd.JSON)
+}
+
+func (cmd *WhichCmd) Run() error {
+	return printLocation(cmd.Path, cmd.JSON)
+}
+
+// Prints the ID of the area, category or entry a path is in, looking it up in
+// the store it's inside of. It's meant for shell prompts, so the store is only
+// read.
+func printLocation(p string, asJSON bool) error {
+	dir := p
+	if info, err := os.Stat(p); err == nil && !info.IsDir() {
+		dir = filepath.Dir(p)
+	}
+
+	root, err := jdfs.FindStore(dir)
+	if err != nil {
+		return err
+	}
+
+	store, err := jdfs.OpenStoreReadOnly(root)
+	if err != nil {
+		return err
+	}
+
+	id, err := store.Locate(p)
+	if err != nil {
+		return err
+	}
+
+	name, err := store.Index.Name(id)
+	if err != nil {
+		return err
+	}
+
+	if !asJSON {
+		fmt.Printf("%s %s\n", id.String(), name)
+		return nil
+	}
+
+	dirPath, err := store.Path(id)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(location{
+		ID:    id.String(),
+		Level: id.Level().String(),
+		Name:  name,
+		Path:  dirPath,
+		Store: store.Root,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,174 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+)
+
+type HistoryCmd struct {
+	ID    *string `arg:"" optional:"" help:"ID of the area, category or entry whose history to show."`
+	Limit int     `short:"n" help:"Show only the most recent changes."`
+	JSON  bool    `help:"Print the changes as JSON lines."`
+}
+
+func (cmd *HistoryCmd) Run() error {
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	var id jdex.ACID
+	if cmd.ID != nil {
+		store, id, err = resolveID(store, *cmd.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	records, err := store.AuditLog()
+	if err != nil {
+		return err
+	}
+
+	if cmd.ID != nil {
+		records = slices.DeleteFunc(records, func(record jdfs.AuditRecord) bool {
+			return !auditRecordWithin(record, id)
+		})
+	}
+
+	if cmd.Limit > 0 && len(records) > cmd.Limit {
+		records = records[len(records)-cmd.Limit:]
+	}
+
+	if cmd.JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, record := range records {
+		fmt.Printf("%s %s@%s %s\n",
+			record.Time.Local().Format(time.DateTime), record.User, record.Host, describeAuditRecord(record))
+		if record.Command != "" {
+			fmt.Printf("  %s\n", record.Command)
+		}
+	}
+
+	return nil
+}
+
+// Whether a record is about id or something below it.
+func auditRecordWithin(record jdfs.AuditRecord, id jdex.ACID) bool {
+	recordID, _, err := jdex.ParseID(record.ID)
+	if err != nil {
+		return false
+	}
+
+	switch id.Level() {
+	case jdex.LevelArea:
+		return recordID.Area == id.Area
+	case jdex.LevelCategory:
+		return recordID.CategoryID() == id.CategoryID()
+	case jdex.LevelEntry:
+		return recordID.EntryID() == id.EntryID()
+	}
+
+	return recordID == id
+}
+
+func describeAuditRecord(record jdfs.AuditRecord) string {
+	before, after := record.Before, record.After
+	if before == nil {
+		before = &jdfs.AuditValues{}
+	}
+	if after == nil {
+		after = &jdfs.AuditValues{}
+	}
+
+	switch record.Change {
+	case jdex.EventAdded.String():
+		return fmt.Sprintf("%s added %q", record.ID, after.Name)
+	case jdex.EventRenamed.String():
+		return fmt.Sprintf("%s renamed %q to %q", record.ID, before.Name, after.Name)
+	case jdex.EventRemoved.String():
+		return fmt.Sprintf("%s removed %q", record.ID, before.Name)
+	case jdex.EventMetadataChanged.String():
+		return fmt.Sprintf("%s changed %s", record.ID, strings.Join(describeAuditChanges(before, after), ", "))
+	case "move":
+		return strings.TrimSpace(fmt.Sprintf("%s moved %q to %q", record.ID, before.Path, after.Path))
+	case "mkdir":
+		return strings.TrimSpace(fmt.Sprintf("%s made %q", record.ID, after.Path))
+	case "rmdir":
+		return strings.TrimSpace(fmt.Sprintf("%s removed %q", record.ID, before.Path))
+	}
+
+	return fmt.Sprintf("%s %s", record.ID, record.Change)
+}
+
+// List the tags and metadata keys which differ, such as `+#tax` or
+// `Bank: Kiwibank -> ANZ`.
+func describeAuditChanges(before, after *jdfs.AuditValues) (changes []string) {
+	for _, tag := range after.Tags {
+		if !slices.Contains(before.Tags, tag) {
+			changes = append(changes, "+#"+tag)
+		}
+	}
+
+	for _, tag := range before.Tags {
+		if !slices.Contains(after.Tags, tag) {
+			changes = append(changes, "-#"+tag)
+		}
+	}
+
+	keys := slices.Collect(maps.Keys(before.Metadata))
+	for key := range after.Metadata {
+		if _, ok := before.Metadata[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		oldValue, hadOld := before.Metadata[key]
+		newValue, hasNew := after.Metadata[key]
+		switch {
+		case !hadOld:
+			changes = append(changes, fmt.Sprintf("%s: %s", key, newValue))
+		case !hasNew:
+			changes = append(changes, fmt.Sprintf("%s: (removed)", key))
+		case oldValue != newValue:
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, oldValue, newValue))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,135 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+)
+
+type ListCmd struct {
+	Select string `arg:"" optional:"" help:"Only list entries matching this ID, range, pattern or name, such as 11.01-11.20, 1* or invoices."`
+
+	Tag     []string `short:"t" help:"Only list entries with this tag. Can be repeated."`
+	Since   string   `help:"Only list entries modified on or after this date."`
+	Before  string   `help:"Only list entries modified before this date."`
+	Created bool     `help:"Filter --since and --before on when entries were created instead."`
+	Local   bool     `help:"Only list the current store, not other registered systems."`
+
+	since  time.Time
+	before time.Time
+}
+
+func (cmd *ListCmd) Run() (err error) {
+	if cmd.Since != "" {
+		cmd.since, err = parseDateArg(cmd.Since)
+		if err != nil {
+			return
+		}
+	}
+
+	if cmd.Before != "" {
+		cmd.before, err = parseDateArg(cmd.Before)
+		if err != nil {
+			return
+		}
+	}
+
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	local := cmd.Local
+	var registry *jdfs.Registry
+	if !local {
+		registry, err = openRegistry(store)
+		if err != nil {
+			return err
+		}
+	}
+
+	var selector jdex.ACIDSelector = jdex.ACIDPattern("*.*")
+	if cmd.Select != "" && local {
+		selector, err = parseSelector(store, cmd.Select)
+	} else if cmd.Select != "" {
+		selector, local, err = parseRegistrySelector(registry, cmd.Select)
+	}
+
+	if err != nil {
+		return
+	}
+
+	if local {
+		for _, entry := range store.Index.EntriesIn(selector) {
+			if cmd.matches(entry) {
+				printEntryLine(entry)
+			}
+		}
+
+		return nil
+	}
+
+	ids, err := registry.Select(selector)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		entry, _ := registry.Entry(id)
+		if cmd.matches(entry) {
+			printEntryLine(entry)
+		}
+	}
+
+	return nil
+}
+
+func (cmd *ListCmd) matches(entry jdex.Entry) bool {
+	for _, tag := range cmd.Tag {
+		if !entry.HasTag(tag) {
+			return false
+		}
+	}
+
+	timestamp := entry.Modified
+	if cmd.Created {
+		timestamp = entry.Created
+	}
+
+	if !cmd.since.IsZero() && timestamp.Before(cmd.since) {
+		return false
+	}
+
+	if !cmd.before.IsZero() && !timestamp.Before(cmd.before) {
+		return false
+	}
+
+	return true
+}
+
+func printEntryLine(entry jdex.Entry) {
+	line := fmt.Sprintf("%s %s", entry.ID.String(), entry.Name)
+	for _, tag := range entry.Tags {
+		line += " #" + tag
+	}
+
+	fmt.Println(line)
+}
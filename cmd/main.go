@@ -19,6 +19,7 @@ package main
 import (
 	"errors"
 	"os"
+	"strings"
 
 	"github.com/alecthomas/kong"
 	"github.com/itisrazza/rzjd/rzinteractive"
@@ -26,19 +27,46 @@ import (
 
 var cli struct {
 	Store          *string `short:"s" type:"path" help:"Path to where your system is stored."`
+	Profile        *string `short:"p" help:"Profile to use, as set up with rzjd profile add."`
 	NonInteractive bool    `default:"false" help:"Fail instead of interactively solving issues."`
 
-	New     NewCmd     `cmd:"" help:"Create a new store."`
-	Explore ExploreCmd `cmd:"" default:"true" help:"Explore your store interactively."`
-	View    ViewCmd    `cmd:"" help:"View an entry in the store."`
-	Edit    EditCmd    `cmd:"" help:"Edit an entry in the store."`
-	Archive ArchiveCmd `cmd:"" help:"Archive an entry."`
-	Setup   SetupCmd   `cmd:"" help:"Set up rzjd in your environment."`
+	New        NewCmd        `cmd:"" help:"Create a new entry or category."`
+	Explore    ExploreCmd    `cmd:"" default:"true" help:"Explore your store interactively."`
+	View       ViewCmd       `cmd:"" help:"View an area, category or entry in the store."`
+	Edit       EditCmd       `cmd:"" help:"Edit the notes of an area, category or entry."`
+	Move       MoveCmd       `cmd:"" name:"mv" help:"Renumber an entry."`
+	Here       HereCmd       `cmd:"" help:"Show which area, category or entry the current directory is in."`
+	Which      WhichCmd      `cmd:"" help:"Show which area, category or entry a path is in."`
+	List       ListCmd       `cmd:"" name:"ls" aliases:"list" help:"List the entries in the store."`
+	Find       FindCmd       `cmd:"" help:"Find the entries matching a query."`
+	Search     SearchCmd     `cmd:"" help:"Search the notes, file names and metadata of entries."`
+	Tag        TagCmd        `cmd:"" help:"Manage the tags of entries."`
+	Undo       UndoCmd       `cmd:"" help:"Undo the last changes made to the store."`
+	Redo       RedoCmd       `cmd:"" help:"Redo changes which were undone."`
+	History    HistoryCmd    `cmd:"" aliases:"log" help:"Show the changes made to the store, or to one area, category or entry."`
+	Archive    ArchiveCmd    `cmd:"" help:"Archive an entry."`
+	Export     ExportCmd     `cmd:"" help:"Export the system in other formats."`
+	Validate   ValidateCmd   `cmd:"" help:"List entries which don't conform to their schema."`
+	Scheme     SchemeCmd     `cmd:"" help:"Show or change which IDs the store allows."`
+	System     SystemCmd     `cmd:"" help:"Manage the systems IDs such as W01.15.14 refer to."`
+	Reorganize ReorganizeCmd `cmd:"" help:"Reorganise an existing folder hierarchy into the store."`
+	Profiles   ProfileCmd    `cmd:"" name:"profile" help:"Manage named stores and their settings."`
+	Setup      SetupCmd      `cmd:"" help:"Set up rzjd in your environment."`
 }
 
 func main() {
 	ctx := kong.Parse(&cli)
-	err := ctx.Run()
+
+	// the profile commands are how a missing profile gets fixed, so they
+	// don't load one
+	var err error
+	if !strings.HasPrefix(ctx.Command(), "profile ") {
+		err = loadProfile()
+	}
+
+	if err == nil {
+		err = ctx.Run()
+	}
 
 	if errors.Is(err, rzinteractive.ErrCancel) {
 		os.Exit(1)
@@ -0,0 +1,98 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+type MoveCmd struct {
+	ID    string `arg:"" help:"ID or pattern of the entry to renumber."`
+	NewID string `arg:"" help:"ID to give the entry."`
+}
+
+func (cmd *MoveCmd) Run() error {
+	to, err := jdex.ParseACID(cmd.NewID)
+	if err != nil {
+		return err
+	}
+
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	store, from, err := resolveID(store, cmd.ID)
+	if err != nil {
+		return err
+	}
+
+	if to.System != "" {
+		registry, err := openRegistry(store)
+		if err != nil {
+			return err
+		}
+
+		toStore, local, err := registry.Resolve(to)
+		if err != nil {
+			return err
+		}
+
+		if toStore != store {
+			return fmt.Errorf("%s is in another system, entries can only be renumbered within their own", to.String())
+		}
+
+		to = local
+	}
+
+	if level := from.Level(); level != jdex.LevelEntry && level != jdex.LevelSub {
+		return fmt.Errorf("%s is not an entry, only entries can be renumbered", from.String())
+	}
+
+	err = store.ScanNoteLinks()
+	if err != nil {
+		return err
+	}
+
+	var dangling []jdex.Link
+	err = recordChange(store, func() (err error) {
+		dangling, err = store.RenumberEntry(from, to)
+		return
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Renumbered %s to %s.\n", from.String(), to.String())
+	printDanglingLinks(dangling)
+	return nil
+}
+
+func printDanglingLinks(links []jdex.Link) {
+	for _, link := range links {
+		if link.Key != "" {
+			fmt.Fprintf(os.Stderr, "warning: %s still refers to %s in %q\n",
+				link.From.String(), link.To.String(), link.Key)
+		} else {
+			fmt.Fprintf(os.Stderr, "warning: %s still links to %s in its notes\n",
+				link.From.String(), link.To.String())
+		}
+	}
+}
@@ -16,12 +16,160 @@
 
 package main
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/itisrazza/rzjd/rzinteractive"
+)
 
 type NewCmd struct {
+	Parent string            `arg:"" help:"Category to create the entry in, such as 12, entry to create a sub-entry of, such as 12.01, or area to create a category in, such as 10-19."`
+	Name   string            `arg:"" optional:"" help:"Name of the new entry or category."`
+	Set    map[string]string `short:"m" help:"Set a metadata value, as Key=Value."`
 }
 
 func (cmd *NewCmd) Run() error {
-	fmt.Println("not implemented")
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	store, parentID, err := resolveID(store, cmd.Parent)
+	if err != nil {
+		return err
+	}
+
+	switch parentID.Level() {
+	case jdex.LevelArea:
+		return cmd.newCategory(store, parentID)
+	case jdex.LevelCategory, jdex.LevelEntry:
+		return cmd.newEntry(store, parentID)
+	}
+
+	return fmt.Errorf("%s is a sub-entry, expected an area, category or entry", parentID.String())
+}
+
+func (cmd *NewCmd) newCategory(store *jdfs.Store, areaID jdex.ACID) error {
+	if len(cmd.Set) > 0 {
+		return errors.New("metadata can only be set on new entries")
+	}
+
+	id, err := store.Index.NextCategoryID(areaID)
+	if err != nil {
+		return err
+	}
+
+	name := cmd.Name
+	if name == "" {
+		if cli.NonInteractive {
+			return errors.New("a name is needed for the new category")
+		}
+
+		err = rzinteractive.NewEntryPrompt(id, &name, nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = recordChange(store, func() error {
+		return store.PutCategory(id, name)
+	})
+	if err != nil {
+		return err
+	}
+
+	categoryPath, err := store.CategoryPath(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s %s in \"%s\".\n", id.String(), name, categoryPath)
+	return nil
+}
+
+// Creates an entry in a category, or a sub-entry of an entry.
+func (cmd *NewCmd) newEntry(store *jdfs.Store, parentID jdex.ACID) error {
+	var id jdex.ACID
+	var err error
+	if parentID.Level() == jdex.LevelEntry {
+		id, err = store.Index.NextSubID(parentID)
+	} else {
+		id, err = store.Index.NextEntryID(parentID)
+	}
+	if err != nil {
+		return err
+	}
+
+	schema, err := store.Index.Schema(parentID)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{}
+	for key, value := range cmd.Set {
+		values[key] = value
+	}
+
+	// what the new entry inherits from where it goes
+	inherited, err := store.Index.EffectiveMetadata(parentID)
+	if err != nil {
+		return err
+	}
+
+	var missing []jdex.Field
+	for _, key := range schema.Keys() {
+		field := schema[key]
+		if _, ok := values[key]; ok || !field.Required || field.Default != nil {
+			continue
+		}
+
+		if value, ok := inherited[key]; ok {
+			if _, err := field.Convert(value); err == nil {
+				continue
+			}
+		}
+
+		missing = append(missing, field)
+	}
+
+	name := cmd.Name
+	if name == "" || len(missing) > 0 {
+		if cli.NonInteractive {
+			if name == "" {
+				return errors.New("a name is needed for the new entry")
+			}
+		} else {
+			err = rzinteractive.NewEntryPrompt(id, &name, missing, values)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	entry := jdex.Entry{
+		ID:       id,
+		Name:     name,
+		Metadata: map[string]jdex.Value{},
+	}
+	for key, value := range values {
+		entry.Metadata[key] = jdex.InferValue(value)
+	}
+
+	err = recordChange(store, func() error {
+		return store.PutEntry(entry)
+	})
+	if err != nil {
+		return err
+	}
+
+	entryPath, err := store.EntryPath(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s %s in \"%s\".\n", id.String(), name, entryPath)
 	return nil
 }
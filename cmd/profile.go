@@ -0,0 +1,124 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/itisrazza/rzjd/rzconfig"
+	"github.com/itisrazza/rzjd/rzinteractive"
+)
+
+type ProfileCmd struct {
+	Ls  ProfileLsCmd  `cmd:"" help:"List the profiles, marking the one in use."`
+	Add ProfileAddCmd `cmd:"" help:"Add a profile, or replace one."`
+	Use ProfileUseCmd `cmd:"" help:"Pick the profile used when no other is given."`
+}
+
+type ProfileLsCmd struct{}
+
+type ProfileAddCmd struct {
+	Name  string `arg:"" help:"Name of the profile, such as work."`
+	Store string `arg:"" type:"path" help:"Path to the profile's store."`
+
+	Editor  string `short:"e" help:"Text editor to open notes with."`
+	Theme   string `help:"Theme for interactive prompts."`
+	Archive string `type:"path" help:"Where archived entries are moved to."`
+}
+
+type ProfileUseCmd struct {
+	Name string `arg:"" help:"Name of the profile."`
+	Here bool   `help:"Only use the profile in the current directory and below, with a marker file."`
+}
+
+func (cmd *ProfileLsCmd) Run() error {
+	config, err := rzconfig.Load()
+	if err != nil {
+		return err
+	}
+
+	// profiles aren't loaded for these commands, so one in use can be missing
+	inUse, _, _ := pickProfile(config)
+
+	for _, name := range slices.Sorted(maps.Keys(config.Profiles)) {
+		marker := " "
+		if name == inUse {
+			marker = "*"
+		}
+
+		fmt.Printf("%s %s %s\n", marker, name, config.Profiles[name].Store)
+	}
+
+	return nil
+}
+
+func (cmd *ProfileAddCmd) Run() error {
+	if cmd.Theme != "" && !slices.Contains(rzinteractive.Themes(), cmd.Theme) {
+		return fmt.Errorf("%w: %q", rzinteractive.ErrUnknownTheme, cmd.Theme)
+	}
+
+	config, err := rzconfig.Load()
+	if err != nil {
+		return err
+	}
+
+	storePath, err := filepath.Abs(cmd.Store)
+	if err != nil {
+		return err
+	}
+
+	err = config.AddProfile(cmd.Name, rzconfig.Profile{
+		Store:   storePath,
+		Editor:  cmd.Editor,
+		Theme:   cmd.Theme,
+		Archive: cmd.Archive,
+	})
+	if err != nil {
+		return err
+	}
+
+	if config.Profile == "" {
+		config.Profile = cmd.Name
+	}
+
+	return config.Save()
+}
+
+func (cmd *ProfileUseCmd) Run() error {
+	config, err := rzconfig.Load()
+	if err != nil {
+		return err
+	}
+
+	if cmd.Here {
+		if _, err := config.LookupProfile(cmd.Name); err != nil {
+			return err
+		}
+
+		return rzconfig.WriteProfileMarker(".", cmd.Name)
+	}
+
+	err = config.UseProfile(cmd.Name)
+	if err != nil {
+		return err
+	}
+
+	return config.Save()
+}
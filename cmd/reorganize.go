@@ -0,0 +1,135 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/itisrazza/rzjd/rzinteractive"
+)
+
+type ReorganizeCmd struct {
+	Path string `arg:"" optional:"" type:"existingdir" help:"Folder hierarchy to reorganise."`
+
+	Plan      string  `type:"existingfile" help:"Apply an edited plan instead of proposing one."`
+	WritePlan *string `type:"path" help:"Write the proposed plan to a file for editing."`
+	Rollback  string  `type:"existingfile" help:"Undo a reorganisation using its rollback log."`
+}
+
+func (cmd *ReorganizeCmd) Run() error {
+	if cmd.Rollback != "" {
+		return cmd.rollback(cmd.Rollback)
+	}
+
+	// a proposed plan is only applied once someone has looked over it
+	if cli.NonInteractive && cmd.Plan == "" && cmd.WritePlan == nil {
+		return errors.New("a proposed plan needs reviewing, so --plan or --write-plan is needed with --non-interactive")
+	}
+
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	plan, err := cmd.plan(store)
+	if err != nil {
+		return err
+	}
+
+	if cmd.WritePlan != nil {
+		return cmd.writePlan(plan, *cmd.WritePlan)
+	}
+
+	if cmd.Plan == "" && !cli.NonInteractive {
+		err = rzinteractive.ReviewPlanPrompt(plan)
+		if err != nil {
+			return err
+		}
+	}
+
+	logPath, err := store.SystemFilePath(
+		fmt.Sprintf("Reorganisation %s.jsonl", time.Now().Format("2006-01-02 150405")),
+	)
+	if err != nil {
+		return err
+	}
+
+	logFile, err := jdfs.CreateWithParents(logPath)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	err = recordChange(store, func() error {
+		return store.ApplyPlan(plan, logFile)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Reorganised \"%s\" into \"%s\".\n", plan.Source, store.Root)
+	fmt.Printf("To undo this, run: rzjd reorganize --rollback \"%s\"\n", logPath)
+	return nil
+}
+
+func (cmd *ReorganizeCmd) plan(store *jdfs.Store) (*jdfs.Plan, error) {
+	if cmd.Plan != "" {
+		planFile, err := os.Open(cmd.Plan)
+		if err != nil {
+			return nil, err
+		}
+		defer planFile.Close()
+
+		return jdfs.ReadPlan(planFile)
+	}
+
+	if cmd.Path == "" {
+		return nil, errors.New("a folder to reorganise or a plan is needed")
+	}
+
+	return jdfs.ProposePlan(cmd.Path, store.Root)
+}
+
+func (cmd *ReorganizeCmd) writePlan(plan *jdfs.Plan, planPath string) error {
+	planFile, err := os.Create(planPath)
+	if err != nil {
+		return err
+	}
+	defer planFile.Close()
+
+	err = jdfs.WritePlan(plan, planFile)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Plan written to \"%s\". Apply it with: rzjd reorganize --plan \"%s\"\n", planPath, planPath)
+	return nil
+}
+
+func (cmd *ReorganizeCmd) rollback(logPath string) error {
+	logFile, err := os.Open(logPath)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	return jdfs.RollbackPlan(logFile)
+}
@@ -0,0 +1,59 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+type SchemeCmd struct {
+	Scheme string `arg:"" optional:"" help:"Scheme to switch to: strict, base36, extended, or decimal:C:E and base36:C:E for custom category and entry widths."`
+}
+
+func (cmd *SchemeCmd) Run() error {
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	if cmd.Scheme == "" {
+		fmt.Println(store.Index.Scheme().String())
+		return nil
+	}
+
+	scheme, err := jdex.ParseIDScheme(cmd.Scheme)
+	if err != nil {
+		return err
+	}
+
+	err = recordChange(store, func() error {
+		err := store.Index.SetScheme(scheme)
+		if err != nil {
+			return err
+		}
+
+		return store.Save()
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Switched to the %s ID scheme.\n", scheme.String())
+	return nil
+}
@@ -0,0 +1,124 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/itisrazza/rzjd/jdfs"
+)
+
+type SearchCmd struct {
+	Terms []string `arg:"" help:"Words to search for. Words ending with * match any word they start. #tags only keep entries with that tag."`
+
+	Limit    int  `short:"n" default:"20" help:"Only show this many entries, or 0 for all of them."`
+	Snippets int  `default:"3" help:"Lines to show where each entry matched."`
+	Local    bool `help:"Only search the current store, not other registered systems."`
+}
+
+func (cmd *SearchCmd) Validate() error {
+	if cmd.Snippets < 0 {
+		return fmt.Errorf("--snippets must not be negative, got %d", cmd.Snippets)
+	}
+
+	return nil
+}
+
+func (cmd *SearchCmd) Run() error {
+	var words, tags []string
+	for _, term := range cmd.Terms {
+		if tag, ok := strings.CutPrefix(term, "#"); ok {
+			tags = append(tags, tag)
+		} else {
+			words = append(words, term)
+		}
+	}
+	query := strings.Join(words, " ")
+
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	hits, err := searchTagged(store, query, tags)
+	if err != nil {
+		return err
+	}
+
+	if !cmd.Local {
+		registry, err := openRegistry(store)
+		if err != nil {
+			return err
+		}
+
+		for _, code := range registry.Codes() {
+			system, err := registry.Store(code)
+			if err != nil {
+				return err
+			}
+
+			if system == store {
+				continue
+			}
+
+			systemHits, err := searchTagged(system, query, tags)
+			if err != nil {
+				return fmt.Errorf("system %s: %w", code, err)
+			}
+
+			for _, hit := range systemHits {
+				hit.ID.System = code
+				hits = append(hits, hit)
+			}
+		}
+
+		slices.SortStableFunc(hits, func(a, b jdfs.SearchHit) int {
+			return cmp.Compare(b.Score, a.Score)
+		})
+	}
+
+	if cmd.Limit > 0 && len(hits) > cmd.Limit {
+		hits = hits[:cmd.Limit]
+	}
+
+	for _, hit := range hits {
+		fmt.Printf("%s %s\n", hit.ID.String(), hit.Name)
+		for _, snippet := range hit.Snippets[:min(len(hit.Snippets), cmd.Snippets)] {
+			fmt.Printf("    %s: %s\n", snippet.Kind, snippet.Text)
+		}
+	}
+
+	return nil
+}
+
+// Search a store, keeping only the entries with every one of tags.
+func searchTagged(store *jdfs.Store, query string, tags []string) ([]jdfs.SearchHit, error) {
+	hits, err := store.Search(query)
+	if err != nil || len(tags) == 0 {
+		return hits, err
+	}
+
+	return slices.DeleteFunc(hits, func(hit jdfs.SearchHit) bool {
+		entry, err := store.Index.Entry(hit.ID)
+		return err != nil || slices.ContainsFunc(tags, func(tag string) bool {
+			return !entry.HasTag(tag)
+		})
+	}), nil
+}
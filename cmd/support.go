@@ -18,27 +18,270 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/adrg/xdg"
+	"github.com/itisrazza/rzjd/jdex"
 	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/itisrazza/rzjd/rzconfig"
 	"github.com/itisrazza/rzjd/rzinteractive"
 )
 
+// Profile picked for this invocation, if any.
+var profile rzconfig.Profile
+
+// Whether the profile was picked by --profile, `RZJD_PROFILE` or a marker,
+// rather than being the default, so its store is the one to use.
+var profileExplicit bool
+
+// Load the profile picked by pickProfile.
+func loadProfile() error {
+	config, err := rzconfig.Load()
+	if err != nil {
+		return err
+	}
+
+	name, explicit, err := pickProfile(config)
+	if err != nil || name == "" {
+		return err
+	}
+
+	profile, err = config.LookupProfile(name)
+	if err != nil {
+		return err
+	}
+
+	profileExplicit = explicit
+
+	if profile.Theme != "" {
+		return rzinteractive.SetTheme(profile.Theme)
+	}
+
+	return nil
+}
+
+// Pick the profile for the current directory, honouring --profile.
+func pickProfile(config *rzconfig.Config) (name string, explicit bool, err error) {
+	return config.PickProfile(cli.Profile, ".")
+}
+
+// Get the path to the store to use, from --store, a profile picked by
+// --profile, `RZJD_PROFILE` or a marker, `RZJD_STORE`, the store the current
+// directory is in, the default profile, or the default in the user's
+// documents, in that order.
 func fullStorePath() (string, error) {
 	if cli.Store != nil {
 		return *cli.Store, nil
 	}
 
-	storePath, ok := os.LookupEnv("RZJD_STORE")
-	if ok {
-		return storePath, nil
+	if !profileExplicit {
+		storePath, ok := os.LookupEnv("RZJD_STORE")
+		if ok {
+			return storePath, nil
+		}
+
+		storePath, err := jdfs.FindStore(".")
+		if err == nil {
+			return storePath, nil
+		} else if !errors.Is(err, jdfs.ErrStoreNotFound) {
+			return "", err
+		}
+	}
+
+	if profile.Store != "" {
+		return profile.Store, nil
 	}
 
 	return path.Join(xdg.UserDirs.Documents, "rzjd"), nil
 }
 
+// Get the area, category or entry input refers to. It can be an ID, such as
+// `11.03` or `W01.15.14`; an ID relative to the current directory, such as
+// `.03`; part of a name; or a range or pattern picking out a single entry.
+// IDs with a system code are looked up in that system's store, which is
+// returned along with the ID local to it.
+func resolveID(store *jdfs.Store, input string) (*jdfs.Store, jdex.ACID, error) {
+	var systems []string
+	if registry, err := openRegistry(store); err == nil {
+		systems = registry.Codes()
+	}
+
+	id, _, err := jdex.ParseIDIn(input, systems)
+	if err != nil && isSelectorPattern(input) {
+		id, err = selectEntry(store, input)
+		return store, id, err
+	}
+
+	if err == nil && id.System != "" {
+		registry, err := openRegistry(store)
+		if err != nil {
+			return nil, jdex.ACID{}, err
+		}
+
+		store, id, err = registry.Resolve(id)
+		if err != nil {
+			return nil, jdex.ACID{}, err
+		}
+
+		if _, err := store.Index.Name(id); err != nil {
+			return nil, jdex.ACID{}, fmt.Errorf("%s: %w", input, err)
+		}
+
+		return store, id, nil
+	}
+
+	id, err = resolveLocal(store, input)
+	return store, id, err
+}
+
+// Resolve input against the store's index, asking which was meant if it's
+// ambiguous.
+func resolveLocal(store *jdfs.Store, input string) (jdex.ACID, error) {
+	id, candidates, err := store.Index.Resolve(input, cwdLocation(store))
+	if !errors.Is(err, jdex.ErrAmbiguous) {
+		return id, err
+	}
+
+	if cli.NonInteractive {
+		const shown = 10
+
+		matches := make([]string, 0, shown)
+		for _, candidate := range candidates[:min(len(candidates), shown)] {
+			matches = append(matches, fmt.Sprintf("%s %s", candidate.ID.String(), candidate.Name))
+		}
+
+		if len(candidates) > shown {
+			matches = append(matches, "...")
+		}
+
+		return jdex.ACID{}, fmt.Errorf("%q could refer to %d things: %s", input, len(candidates), strings.Join(matches, ", "))
+	}
+
+	return rzinteractive.PickCandidatePrompt(input, candidates)
+}
+
+// Get where the current directory is in the store, if it's in it.
+func cwdLocation(store *jdfs.Store) jdex.ACID {
+	id, _ := store.Locate(".")
+	return id
+}
+
+// Whether input is a range or pattern rather than something to resolve.
+func isSelectorPattern(input string) bool {
+	if strings.ContainsAny(input, "*?") {
+		return true
+	}
+
+	_, err := jdex.ParseACIDRange(input)
+	return err == nil
+}
+
+// Get the registry of systems from the config, with store as the local one.
+func openRegistry(store *jdfs.Store) (*jdfs.Registry, error) {
+	config, err := rzconfig.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return jdfs.NewRegistry(store, config.Systems), nil
+}
+
+// Parse an ID, range or pattern into a selector. Anything else is resolved to
+// an area, category or entry, selecting the entries in it.
+func parseSelector(store *jdfs.Store, input string) (jdex.ACIDSelector, error) {
+	selector, err := jdex.ParseACIDSelector(input)
+	if err == nil {
+		return selector, nil
+	}
+
+	id, err := resolveLocal(store, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return jdex.IDSelector(id), nil
+}
+
+// Parse a selector like parseSelector, for every system in the registry. What
+// is resolved by name is in the local store, so that selector carries the
+// local store's system code. If the local store has no code, local is true and
+// the selector only means something in the local store.
+func parseRegistrySelector(registry *jdfs.Registry, input string) (selector jdex.ACIDSelector, local bool, err error) {
+	selector, err = jdex.ParseACIDSelector(input)
+	if err == nil {
+		return
+	}
+
+	id, err := resolveLocal(registry.Local, input)
+	if err != nil {
+		return
+	}
+
+	if codes := registry.LocalCodes(); len(codes) > 0 {
+		id.System = codes[0]
+	}
+
+	return jdex.IDSelector(id), id.System == "", nil
+}
+
+// Get the entries picked out by an ID, range, pattern or name.
+func selectEntries(store *jdfs.Store, input string) ([]jdex.ACID, error) {
+	selector, err := parseSelector(store, input)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := store.Index.Select(selector)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%q doesn't match any entries", input)
+	}
+
+	return ids, nil
+}
+
+// Get the single entry picked out by an ID, range or pattern.
+func selectEntry(store *jdfs.Store, input string) (jdex.ACID, error) {
+	ids, err := selectEntries(store, input)
+	if err != nil {
+		return jdex.ACID{}, err
+	}
+
+	if len(ids) > 1 {
+		matches := make([]string, len(ids))
+		for n, id := range ids {
+			matches[n] = id.String()
+		}
+
+		return jdex.ACID{}, fmt.Errorf("%q matches %d entries: %s", input, len(ids), strings.Join(matches, ", "))
+	}
+
+	return ids[0], nil
+}
+
+// Run the changes a command makes to a store as one operation, so they can be
+// reverted with rzjd undo.
+func recordChange(store *jdfs.Store, change func() error) error {
+	return store.Record(strings.Join(os.Args[1:], " "), change)
+}
+
+// Parses a date, such as `2025-06-30`, or a date and time in RFC 3339 form.
+func parseDateArg(input string) (time.Time, error) {
+	if date, err := time.ParseInLocation(jdex.DateLayout, input, time.Local); err == nil {
+		return date, nil
+	}
+
+	dateTime, err := time.Parse(time.RFC3339, input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date", input)
+	}
+
+	return dateTime, nil
+}
+
 func OpenOrCreateStore() (*jdfs.Store, error) {
 	storePath, err := fullStorePath()
 	if err != nil {
@@ -0,0 +1,89 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/itisrazza/rzjd/rzconfig"
+)
+
+type SystemCmd struct {
+	Ls  SystemLsCmd  `cmd:"" help:"List the registered systems."`
+	Add SystemAddCmd `cmd:"" help:"Register the store a system is kept in."`
+	Rm  SystemRmCmd  `cmd:"" help:"Forget about a system, leaving its store alone."`
+}
+
+type SystemLsCmd struct{}
+
+type SystemAddCmd struct {
+	Code string `arg:"" help:"System code, such as W01."`
+	Root string `arg:"" type:"existingdir" help:"Path to the system's store."`
+}
+
+type SystemRmCmd struct {
+	Code string `arg:"" help:"System code, such as W01."`
+}
+
+func (cmd *SystemLsCmd) Run() error {
+	config, err := rzconfig.Load()
+	if err != nil {
+		return err
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(config.Systems)) {
+		fmt.Printf("%s %s\n", code, config.Systems[code])
+	}
+
+	return nil
+}
+
+func (cmd *SystemAddCmd) Run() error {
+	config, err := rzconfig.Load()
+	if err != nil {
+		return err
+	}
+
+	root, err := filepath.Abs(cmd.Root)
+	if err != nil {
+		return err
+	}
+
+	err = config.AddSystem(cmd.Code, root)
+	if err != nil {
+		return err
+	}
+
+	return config.Save()
+}
+
+func (cmd *SystemRmCmd) Run() error {
+	config, err := rzconfig.Load()
+	if err != nil {
+		return err
+	}
+
+	err = config.RemoveSystem(cmd.Code)
+	if err != nil {
+		return err
+	}
+
+	return config.Save()
+}
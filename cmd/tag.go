@@ -0,0 +1,130 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+type TagCmd struct {
+	Add TagAddCmd `cmd:"" help:"Add tags to an entry."`
+	Rm  TagRmCmd  `cmd:"" help:"Remove tags from an entry."`
+	Ls  TagLsCmd  `cmd:"" help:"List all tags, or the tags of an entry."`
+}
+
+type TagAddCmd struct {
+	ID   string   `arg:"" help:"ID, range or pattern of the entries to tag."`
+	Tags []string `arg:"" help:"Tags to add."`
+}
+
+type TagRmCmd struct {
+	ID   string   `arg:"" help:"ID, range or pattern of the entries to untag."`
+	Tags []string `arg:"" help:"Tags to remove."`
+}
+
+type TagLsCmd struct {
+	ID *string `arg:"" optional:"" help:"ID of the entry whose tags to list."`
+}
+
+func (cmd *TagAddCmd) Run() error {
+	return updateEntryTags(cmd.ID, func(tags []string) ([]string, error) {
+		return append(tags, cmd.Tags...), nil
+	})
+}
+
+func (cmd *TagRmCmd) Run() error {
+	return updateEntryTags(cmd.ID, func(tags []string) ([]string, error) {
+		removed, err := jdex.NormaliseTags(cmd.Tags)
+		if err != nil {
+			return nil, err
+		}
+
+		return slices.DeleteFunc(tags, func(tag string) bool {
+			return slices.Contains(removed, tag)
+		}), nil
+	})
+}
+
+func (cmd *TagLsCmd) Run() error {
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	if cmd.ID != nil {
+		store, id, err := resolveID(store, *cmd.ID)
+		if err != nil {
+			return err
+		}
+
+		entry, err := store.Index.Entry(id)
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range entry.Tags {
+			fmt.Printf("#%s\n", tag)
+		}
+
+		return nil
+	}
+
+	for _, tag := range store.Index.Tags() {
+		fmt.Printf("#%s (%d)\n", tag, len(store.Index.Tagged(tag)))
+	}
+
+	return nil
+}
+
+func updateEntryTags(input string, update func([]string) ([]string, error)) error {
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	ids, err := selectEntries(store, input)
+	if err != nil {
+		return err
+	}
+
+	// every entry is changed, or none of them are
+	return recordChange(store, func() error {
+		tx, err := store.Begin()
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			entry, err := tx.Index.Entry(id)
+			if err == nil {
+				entry.Tags, err = update(slices.Clone(entry.Tags))
+			}
+			if err == nil {
+				err = tx.PutEntry(entry)
+			}
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
+}
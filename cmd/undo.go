@@ -0,0 +1,79 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdfs"
+)
+
+type UndoCmd struct {
+	Count int  `arg:"" optional:"" default:"1" help:"How many changes to undo."`
+	List  bool `short:"l" help:"List the changes which can be undone instead."`
+}
+
+type RedoCmd struct {
+	Count int `arg:"" optional:"" default:"1" help:"How many changes to redo."`
+}
+
+func (cmd *UndoCmd) Run() error {
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	if cmd.List {
+		operations, err := store.UndoHistory()
+		if err != nil {
+			return err
+		}
+
+		for _, operation := range operations {
+			printOperation("", operation)
+		}
+
+		return nil
+	}
+
+	undone, err := store.Undo(cmd.Count)
+	for _, operation := range undone {
+		printOperation("Undid ", operation)
+	}
+
+	return err
+}
+
+func (cmd *RedoCmd) Run() error {
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	redone, err := store.Redo(cmd.Count)
+	for _, operation := range redone {
+		printOperation("Redid ", operation)
+	}
+
+	return err
+}
+
+func printOperation(prefix string, operation jdfs.Operation) {
+	fmt.Printf("%s%q from %s\n", prefix, "rzjd "+operation.Description,
+		operation.Time.Local().Format(time.DateTime))
+}
@@ -0,0 +1,61 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ValidateCmd struct {
+}
+
+func (cmd *ValidateCmd) Run() error {
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	violations := store.Index.Validate()
+	for _, violation := range violations {
+		entry, _ := store.Index.Entry(violation.ID)
+		fmt.Printf("%s %s\n", violation.ID.String(), entry.Name)
+
+		for _, line := range strings.Split(violation.Err.Error(), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+
+	err = store.ScanNoteLinks()
+	if err != nil {
+		return err
+	}
+
+	dangling := store.Index.DanglingLinks()
+	printDanglingLinks(dangling)
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%d entries don't conform to their schema", len(violations))
+	}
+
+	if len(dangling) > 0 {
+		return fmt.Errorf("%d links point to missing entries", len(dangling))
+	}
+
+	fmt.Println("All entries conform to their schema.")
+	return nil
+}
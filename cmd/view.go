@@ -16,12 +16,190 @@
 
 package main
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+)
 
 type ViewCmd struct {
+	ID string `arg:"" help:"ID of the area, category or entry to view, or pattern of the entry."`
 }
 
 func (cmd *ViewCmd) Run() error {
-	fmt.Println("not implemented")
+	store, err := OpenOrCreateStore()
+	if err != nil {
+		return err
+	}
+
+	store, id, err := resolveID(store, cmd.ID)
+	if err != nil {
+		return err
+	}
+
+	switch id.Level() {
+	case jdex.LevelArea:
+		err = viewArea(store, id)
+	case jdex.LevelCategory:
+		err = viewCategory(store, id)
+	default:
+		err = viewEntry(store, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	notesPath, err := store.NotesPath(id)
+	if err != nil {
+		return err
+	}
+
+	notes, err := os.ReadFile(notesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s", notes)
+	return nil
+}
+
+func viewArea(store *jdfs.Store, id jdex.ACID) error {
+	name, err := store.Index.AreaName(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s\n", id.String(), name)
+
+	metadata, err := store.Index.EffectiveMetadata(id)
+	if err != nil {
+		return err
+	}
+
+	printMetadata(metadata)
+
+	categoryIDs := slices.Collect(store.Index.Children(id))
+	if len(categoryIDs) > 0 {
+		fmt.Printf("\nCategories:\n")
+	}
+
+	for _, categoryID := range categoryIDs {
+		categoryName, _ := store.Index.CategoryName(categoryID)
+		fmt.Printf("  %s %s\n", categoryID.String(), categoryName)
+	}
+
 	return nil
 }
+
+func viewCategory(store *jdfs.Store, id jdex.ACID) error {
+	name, err := store.Index.CategoryName(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s\n", id.String(), name)
+
+	metadata, err := store.Index.EffectiveMetadata(id)
+	if err != nil {
+		return err
+	}
+
+	printMetadata(metadata)
+
+	entryIDs := slices.Collect(store.Index.Children(id))
+	if len(entryIDs) > 0 {
+		fmt.Printf("\nEntries:\n")
+	}
+
+	for _, entryID := range entryIDs {
+		entry, _ := store.Index.Entry(entryID)
+		fmt.Print("  ")
+		printEntryLine(entry)
+	}
+
+	return nil
+}
+
+func viewEntry(store *jdfs.Store, id jdex.ACID) error {
+	entry, err := store.Index.Entry(id)
+	if err != nil {
+		return err
+	}
+
+	err = store.ScanNoteLinks()
+	if err != nil {
+		return err
+	}
+
+	printEntryLine(entry)
+
+	if !entry.Created.IsZero() {
+		fmt.Printf("  Created: %s\n", entry.Created.Local().Format(time.DateTime))
+	}
+	if !entry.Modified.IsZero() {
+		fmt.Printf("  Modified: %s\n", entry.Modified.Local().Format(time.DateTime))
+	}
+
+	metadata, err := store.Index.EffectiveMetadata(id)
+	if err != nil {
+		return err
+	}
+
+	printMetadata(metadata)
+
+	subIDs := slices.Collect(store.Index.Children(id))
+	if len(subIDs) > 0 {
+		fmt.Printf("\nSub-entries:\n")
+	}
+
+	for _, subID := range subIDs {
+		sub, _ := store.Index.Entry(subID)
+		fmt.Print("  ")
+		printEntryLine(sub)
+	}
+
+	printLinks(store, "Links to", store.Index.Links(id), func(link jdex.Link) jdex.ACID {
+		return link.To
+	})
+	printLinks(store, "Linked from", store.Index.Backlinks(id), func(link jdex.Link) jdex.ACID {
+		return link.From
+	})
+
+	return nil
+}
+
+func printMetadata(metadata map[string]jdex.Value) {
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		fmt.Printf("  %s: %s\n", key, metadata[key].String())
+	}
+}
+
+func printLinks(store *jdfs.Store, title string, links []jdex.Link, other func(jdex.Link) jdex.ACID) {
+	if len(links) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s:\n", title)
+	for _, link := range links {
+		id := other(link)
+
+		name := "(missing)"
+		if entry, err := store.Index.Entry(id); err == nil {
+			name = entry.Name
+		}
+
+		if link.Key != "" {
+			fmt.Printf("  %s %s (%s)\n", id.String(), name, link.Key)
+		} else {
+			fmt.Printf("  %s %s\n", id.String(), name)
+		}
+	}
+}
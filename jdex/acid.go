@@ -17,8 +17,11 @@
 package jdex
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -38,12 +41,84 @@ type ACID struct {
 	Sub      string
 }
 
+// Level of the hierarchy an ID refers to.
+type Level int
+
+const (
+	LevelArea     Level = iota + 1 // An area, such as `10-19`.
+	LevelCategory                  // A category, such as `11`.
+	LevelEntry                     // An entry, such as `11.01`.
+	LevelSub                       // A sub-entry, such as `11.01+001`.
+)
+
+var levelNames = map[Level]string{
+	LevelArea:     "area",
+	LevelCategory: "category",
+	LevelEntry:    "entry",
+	LevelSub:      "sub-entry",
+}
+
 var ErrParseACIDBadSeparatorCount = errors.New("ID is expected to have 2 or 3 dot separators")
+var ErrParseACIDMissingPart = errors.New("ID is missing its area, category or entry")
+var ErrParseIDLevel = errors.New("ID is not an area, category or entry")
 var ErrACIDInvalidChars = errors.New("ID contains invalid characters")
 var ErrACIDRemote = errors.New("ID contains a remote when a local one is needed")
 
+// Matches the system prefix, such as `W01`, which sets a system's category
+// apart from an entry in IDs such as `W01.11`.
+var systemPrefixRegex = regexp.MustCompile(`^[A-Z]\d\d$`)
+
+var areaIDRegex = regexp.MustCompile(`^([A-Z0-9])0-([A-Z0-9])9$`)
+
+func (level Level) String() string {
+	name, ok := levelNames[level]
+	if !ok {
+		return fmt.Sprintf("Level(%d)", int(level))
+	}
+
+	return name
+}
+
+// Returns the level the ID refers to, going by which parts are set.
+func (id *ACID) Level() Level {
+	switch {
+	case id.Entry != "" && id.Sub != "":
+		return LevelSub
+	case id.Entry != "":
+		return LevelEntry
+	case id.Category != "":
+		return LevelCategory
+	default:
+		return LevelArea
+	}
+}
+
+// Returns the ID of the area the ID is in.
+func (id *ACID) AreaID() ACID {
+	return ACID{System: id.System, Area: id.Area}
+}
+
+// Returns the ID of the category the ID is in.
+func (id *ACID) CategoryID() ACID {
+	return ACID{System: id.System, Area: id.Area, Category: id.Category}
+}
+
+// Returns the ID of the entry the ID is in, without its sub-entry.
+func (id *ACID) EntryID() ACID {
+	return ACID{System: id.System, Area: id.Area, Category: id.Category, Entry: id.Entry}
+}
+
+// Returns the ID in the form of `AC.ID+SUB`, `AC.ID`, `AC` or `A0-A9`,
+// depending on its level.
 func (id *ACID) String() (str string) {
-	str = fmt.Sprintf("%c%s.%s", id.Area, id.Category, id.Entry)
+	switch id.Level() {
+	case LevelArea:
+		str = id.AreaString()
+	case LevelCategory:
+		str = id.CategoryString()
+	default:
+		str = fmt.Sprintf("%c%s.%s", id.Area, id.Category, id.Entry)
+	}
 
 	if id.System != "" {
 		str = id.System + "." + str
@@ -66,6 +141,8 @@ func (id *ACID) CategoryString() (str string) {
 	return fmt.Sprintf("%c%s", id.Area, id.Category)
 }
 
+// Parse an entry ID, such as `11.01` or `W01.11.01+001`. See ParseID for
+// areas and categories.
 func ParseACID(input string) (acid ACID, err error) {
 	splits := strings.Split(input, ".")
 
@@ -84,6 +161,11 @@ func ParseACID(input string) (acid ACID, err error) {
 		return
 	}
 
+	if len(ac) < 2 || id == "" || id[0] == '+' || id[len(id)-1] == '+' {
+		err = ErrParseACIDMissingPart
+		return
+	}
+
 	acid.Area = ac[0]
 	acid.Category = ac[1:]
 
@@ -99,6 +181,60 @@ func ParseACID(input string) (acid ACID, err error) {
 	return
 }
 
+// Parse an area, category, entry or sub-entry ID, such as `10-19`, `11`,
+// `11.01` or `11.01+001`, with or without a system prefix.
+//
+// A category with a system prefix, such as `W01.11`, reads the same as an
+// entry, so is read as one. See ParseIDIn to read it as a category.
+func ParseID(input string) (id ACID, level Level, err error) {
+	return ParseIDIn(input, nil)
+}
+
+// Parse an ID like ParseID, but reading an ID such as `W01.11` as a category
+// when its system is one of systems.
+func ParseIDIn(input string, systems []string) (id ACID, level Level, err error) {
+	rest := input
+	if system, after, ok := strings.Cut(input, "."); ok && IsSystemCode(system) &&
+		(strings.Contains(after, ".") || areaIDRegex.MatchString(after) || slices.Contains(systems, system)) {
+		id.System = system
+		rest = after
+	}
+
+	switch {
+	case areaIDRegex.MatchString(rest):
+		matches := areaIDRegex.FindStringSubmatch(rest)
+		if matches[1] != matches[2] {
+			err = fmt.Errorf("%w: %q", ErrParseIDLevel, input)
+			return
+		}
+
+		id.Area = matches[1][0]
+	case strings.Contains(rest, "."):
+		id, err = ParseACID(input)
+		if err != nil {
+			return
+		}
+	case len(rest) >= 2 && !strings.ContainsAny(rest, "-+"):
+		id.Area = rest[0]
+		id.Category = rest[1:]
+	default:
+		err = fmt.Errorf("%w: %q", ErrParseIDLevel, input)
+		return
+	}
+
+	if err = id.Valid(); err != nil {
+		return
+	}
+
+	return id, id.Level(), nil
+}
+
+// Checks whether s is a system code, such as `W01`, as used to tell systems
+// apart in IDs.
+func IsSystemCode(s string) bool {
+	return systemPrefixRegex.MatchString(s)
+}
+
 func MustParseACID(input string) (id ACID) {
 	id, err := ParseACID(input)
 	if err != nil {
@@ -141,3 +277,41 @@ func checkACIDCharset(v ...string) error {
 
 	return nil
 }
+
+// Compare two IDs, returning -1, 0 or 1. Each part is compared in turn, with
+// shorter parts first and then characters in ACIDCharset order.
+func (id *ACID) Compare(other ACID) int {
+	return cmp.Or(
+		compareACIDPart(id.System, other.System),
+		compareACIDPart(string(id.Area), string(other.Area)),
+		compareACIDPart(id.Category, other.Category),
+		compareACIDPart(id.Entry, other.Entry),
+		compareACIDPart(id.Sub, other.Sub),
+	)
+}
+
+func (id *ACID) Less(other ACID) bool {
+	return id.Compare(other) < 0
+}
+
+// Compare two IDs, for use with slices.SortFunc and friends.
+func CompareACIDs(a, b ACID) int {
+	return a.Compare(b)
+}
+
+func compareACIDPart(a, b string) int {
+	if len(a) != len(b) {
+		return cmp.Compare(len(a), len(b))
+	}
+
+	for i := range len(a) {
+		if a[i] != b[i] {
+			return cmp.Compare(
+				strings.IndexByte(ACIDCharset, a[i]),
+				strings.IndexByte(ACIDCharset, b[i]),
+			)
+		}
+	}
+
+	return 0
+}
@@ -142,3 +142,132 @@ func TestParseACID_BadAreaChar(t *testing.T) {
 func TestParseACID_BadChar(t *testing.T) {
 	testParseACIDFailure(t, "1Ă.23", jdex.ErrACIDInvalidChars)
 }
+
+//
+// ACID.Compare
+//
+
+func TestACIDCompare(t *testing.T) {
+	ordered := []string{"11.01", "11.02", "11.0A", "11.10", "11.100", "12.01", "1A.01", "21.01"}
+
+	for n := 1; n < len(ordered); n++ {
+		a := jdex.MustParseACID(ordered[n-1])
+		b := jdex.MustParseACID(ordered[n])
+
+		assert.True(t, a.Less(b), "%s < %s", ordered[n-1], ordered[n])
+		assert.Equal(t, 1, b.Compare(a), "%s > %s", ordered[n], ordered[n-1])
+	}
+}
+
+//
+// ParseACIDRange, ParseACIDPattern
+//
+
+func TestParseACIDRange_Entries(t *testing.T) {
+	r, err := jdex.ParseACIDRange("11.01-11.20")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.True(t, r.Match(jdex.MustParseACID("11.01")))
+	assert.True(t, r.Match(jdex.MustParseACID("11.15+001")))
+	assert.True(t, r.Match(jdex.MustParseACID("11.20")))
+	assert.False(t, r.Match(jdex.MustParseACID("11.21")))
+	assert.False(t, r.Match(jdex.MustParseACID("W01.11.05")))
+}
+
+func TestParseACIDRange_Area(t *testing.T) {
+	r, err := jdex.ParseACIDRange("10-19")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.True(t, r.Match(jdex.MustParseACID("10.01")))
+	assert.True(t, r.Match(jdex.MustParseACID("19.99")))
+	assert.False(t, r.Match(jdex.MustParseACID("20.01")))
+}
+
+func TestParseACIDRange_Invalid(t *testing.T) {
+	for _, input := range []string{"11.01", "11-11.02", "11.20-11.01", "1-2-3"} {
+		_, err := jdex.ParseACIDRange(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestParseACIDPattern(t *testing.T) {
+	p, err := jdex.ParseACIDPattern("1*.0*")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.True(t, p.Match(jdex.MustParseACID("12.05")))
+	assert.False(t, p.Match(jdex.MustParseACID("12.15")))
+	assert.False(t, p.Match(jdex.MustParseACID("22.05")))
+
+	category, _ := jdex.ParseACIDPattern("1?")
+	assert.True(t, category.Match(jdex.MustParseACID("12.15")))
+
+	_, err = jdex.ParseACIDPattern("1/*")
+	assert.ErrorIs(t, err, jdex.ErrACIDInvalidChars)
+}
+
+//
+// ParseID
+//
+
+func TestParseID_Levels(t *testing.T) {
+	cases := []struct {
+		input string
+		id    jdex.ACID
+		level jdex.Level
+	}{
+		{"10-19", jdex.ACID{Area: '1'}, jdex.LevelArea},
+		{"11", jdex.ACID{Area: '1', Category: "1"}, jdex.LevelCategory},
+		{"11.01", jdex.ACID{Area: '1', Category: "1", Entry: "01"}, jdex.LevelEntry},
+		{"W01.10-19", jdex.ACID{System: "W01", Area: '1'}, jdex.LevelArea},
+		{"W01.11", jdex.ACID{System: "W01", Area: '1', Category: "1"}, jdex.LevelCategory},
+		{"W01.11.01", jdex.ACID{System: "W01", Area: '1', Category: "1", Entry: "01"}, jdex.LevelEntry},
+		{"A12.34", jdex.ACID{Area: 'A', Category: "12", Entry: "34"}, jdex.LevelEntry},
+	}
+
+	for _, c := range cases {
+		id, level, err := jdex.ParseIDIn(c.input, []string{"W01"})
+		if !assert.NoError(t, err, c.input) {
+			continue
+		}
+
+		assert.Equal(t, c.id, id, c.input)
+		assert.Equal(t, c.level, level, c.input)
+		assert.Equal(t, c.level, id.Level(), c.input)
+		assert.Equal(t, c.input, id.String(), c.input)
+	}
+}
+
+func TestParseID_SystemOrEntry(t *testing.T) {
+	// without W01 registered, only the entry reading is possible
+	id, level, err := jdex.ParseID("W01.11")
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.ACID{Area: 'W', Category: "01", Entry: "11"}, id)
+	assert.Equal(t, jdex.LevelEntry, level)
+
+	id, level, err = jdex.ParseIDIn("A12.34", []string{"W01"})
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.ACID{Area: 'A', Category: "12", Entry: "34"}, id)
+	assert.Equal(t, jdex.LevelEntry, level)
+
+	id, _, err = jdex.ParseID("A12.34.56")
+	assert.NoError(t, err)
+	assert.Equal(t, "A12", id.System)
+}
+
+func TestParseID_Invalid(t *testing.T) {
+	for _, input := range []string{"", "1", "10-29", "11-12", "1a", "11.", ".01", "W01."} {
+		_, _, err := jdex.ParseID(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestParseACID_FailCategory(t *testing.T) {
+	_, err := jdex.ParseACID("11.")
+	assert.ErrorIs(t, err, jdex.ErrParseACIDMissingPart)
+}
@@ -0,0 +1,136 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex_test
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Index_ConcurrentUse(t *testing.T) {
+	index := newWalkIndex()
+	categoryID := jdex.ACID{Area: '1', Category: "1"}
+
+	var wg sync.WaitGroup
+	for writer := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range 20 {
+				id := jdex.MustParseACID(fmt.Sprintf("11.%d%d", writer+3, n%10))
+				assert.NoError(t, index.PutEntry(jdex.Entry{
+					ID:       id,
+					Name:     "Written",
+					Tags:     []string{"busy"},
+					Metadata: map[string]jdex.Value{"Writer": jdex.IntegerValue(int64(writer))},
+				}))
+				index.Snapshot()
+			}
+		}()
+	}
+
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				for id, entry := range index.AllEntries() {
+					assert.Equal(t, id, entry.ID)
+				}
+
+				index.Entries(categoryID)
+				index.Tagged("busy")
+				index.Resolve("written", jdex.ACID{})
+				index.EffectiveMetadata(categoryID)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	entries, _ := index.Entries(categoryID)
+	assert.Len(t, entries, 2+4*10)
+}
+
+func Test_Index_Snapshot(t *testing.T) {
+	index := newWalkIndex()
+	id := jdex.MustParseACID("11.01")
+
+	snapshot := index.Snapshot()
+
+	assert.NoError(t, index.PutEntry(jdex.Entry{ID: id, Name: "Renamed", Tags: []string{"new"}}))
+	assert.NoError(t, index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.03"), Name: "Added"}))
+	_, err := index.RemoveEntry(jdex.MustParseACID("12.01"))
+	assert.NoError(t, err)
+
+	entry, err := snapshot.Entry(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "11.01", entry.Name)
+	assert.Empty(t, snapshot.Tagged("new"))
+
+	entries, _ := snapshot.Entries(id.CategoryID())
+	assert.Len(t, entries, 2)
+
+	_, err = snapshot.Entry(jdex.MustParseACID("12.01"))
+	assert.NoError(t, err)
+
+	// changing the snapshot doesn't reach the index
+	assert.NoError(t, snapshot.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.09"), Name: "Snapshot"}))
+	_, err = index.Entry(jdex.MustParseACID("11.09"))
+	assert.Error(t, err)
+
+	entry, _ = index.Entry(id)
+	assert.Equal(t, "Renamed", entry.Name)
+}
+
+func Test_Index_EntryIsCopied(t *testing.T) {
+	index := newWalkIndex()
+	id := jdex.MustParseACID("11.01")
+
+	metadata := map[string]jdex.Value{"Bank": jdex.StringValue("Kiwibank")}
+	assert.NoError(t, index.PutEntry(jdex.Entry{ID: id, Name: "Savings", Tags: []string{"money"}, Metadata: metadata}))
+	metadata["Bank"] = jdex.StringValue("ANZ")
+
+	entry, _ := index.Entry(id)
+	entry.Metadata["Bank"] = jdex.StringValue("ASB")
+	entry.Tags[0] = "changed"
+
+	entry, _ = index.Entry(id)
+	assert.Equal(t, jdex.StringValue("Kiwibank"), entry.Metadata["Bank"])
+	assert.Equal(t, []string{"money"}, entry.Tags)
+}
+
+func Test_Index_WalkWhileChanging(t *testing.T) {
+	index := newWalkIndex()
+
+	var walked []string
+	for id := range index.AllEntries() {
+		walked = append(walked, id.String())
+		if id.Sub == "" && !jdex.IsProtectedACID(id) {
+			_, err := index.RemoveEntry(id)
+			assert.NoError(t, err)
+		}
+	}
+
+	assert.Equal(t, []string{"00.00", "11.01", "11.01+001", "11.01+002", "11.02", "12.01", "21.01"}, walked)
+	assert.Equal(t, []jdex.ACID{jdex.MustParseACID("00.00")}, slices.Collect(jdex.AtLevel(index.Walk(jdex.ACID{}), jdex.LevelEntry)))
+}
@@ -0,0 +1,235 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// What happened to an area, category or entry.
+type EventKind int
+
+const (
+	EventAdded           EventKind = iota + 1 // It was added to the index.
+	EventRenamed                              // Its name changed.
+	EventMetadataChanged                      // Its metadata, or an entry's tags, changed.
+	EventRemoved                              // It was removed from the index.
+)
+
+var eventKindNames = map[EventKind]string{
+	EventAdded:           "added",
+	EventRenamed:         "renamed",
+	EventMetadataChanged: "metadata changed",
+	EventRemoved:         "removed",
+}
+
+func (kind EventKind) String() string {
+	name, ok := eventKindNames[kind]
+	if !ok {
+		return fmt.Sprintf("EventKind(%d)", int(kind))
+	}
+
+	return name
+}
+
+// A change made to an area, category or entry. Renumbering an entry shows up
+// as its new ID being added and its old ID being removed.
+type Event struct {
+	Kind    EventKind
+	ID      ACID
+	Name    string // Name after the change, or before it for removals.
+	OldName string // Name before a rename.
+
+	// Names of the area, category and, for a sub-entry, entry it is in, as
+	// they were when the change was made.
+	Parents []string
+
+	// An entry's tags and the metadata set on it directly, before and after a
+	// metadata change.
+	Tags, OldTags         []string
+	Metadata, OldMetadata map[string]Value
+}
+
+// Receives the changes made to an index.
+type Observer func(Event)
+
+type registeredObserver struct {
+	id       int
+	observer Observer
+}
+
+// Call observer with every change made to the index from now on, returning a
+// function which stops it.
+//
+// Observers are called one at a time, in the order the changes were made,
+// once the index is unlocked. They may read the index, but mustn't change it.
+func (index *Index) Observe(observer Observer) (stop func()) {
+	index.observersMu.Lock()
+	defer index.observersMu.Unlock()
+
+	index.nextObserver++
+	id := index.nextObserver
+	index.observers = append(index.observers, registeredObserver{id, observer})
+
+	return func() {
+		index.observersMu.Lock()
+		defer index.observersMu.Unlock()
+
+		index.observers = slices.DeleteFunc(slices.Clone(index.observers), func(registered registeredObserver) bool {
+			return registered.id == id
+		})
+	}
+}
+
+// Get a channel of every change made to the index from now on, along with a
+// function which stops and closes it. Changes are held back once buffer of
+// them are waiting, so the channel needs to be kept drained until it's
+// stopped. Changes still waiting when it's stopped are dropped.
+func (index *Index) Events(buffer int) (events <-chan Event, stop func()) {
+	channel := make(chan Event, buffer)
+	done := make(chan struct{})
+	stopObserving := index.Observe(func(event Event) {
+		select {
+		case channel <- event:
+		case <-done:
+		}
+	})
+
+	return channel, func() {
+		// let go of any change still waiting to be sent
+		close(done)
+		stopObserving()
+
+		// wait out any change still being sent
+		index.notifyMu.Lock()
+		defer index.notifyMu.Unlock()
+
+		close(channel)
+	}
+}
+
+// Release the write lock, then pass the changes made while it was held on to
+// the observers.
+func (index *Index) unlock() {
+	events := index.data.pending
+	index.data.pending = nil
+
+	index.notifyMu.Lock()
+	defer index.notifyMu.Unlock()
+
+	index.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	index.observersMu.Lock()
+	observers := index.observers
+	index.observersMu.Unlock()
+
+	for _, event := range events {
+		for _, registered := range observers {
+			registered.observer(event)
+		}
+	}
+}
+
+func (data *indexData) emit(kind EventKind, id ACID, name string) {
+	data.pending = append(data.pending, Event{Kind: kind, ID: id, Name: name, Parents: data.parentNames(id)})
+}
+
+func (data *indexData) emitRename(id ACID, oldName string, name string) {
+	if oldName != name {
+		data.pending = append(data.pending, Event{
+			Kind:    EventRenamed,
+			ID:      id,
+			Name:    name,
+			OldName: oldName,
+			Parents: data.parentNames(id),
+		})
+	}
+}
+
+func (data *indexData) emitMetadata(id ACID, name string, oldTags, tags []string, old, metadata map[string]Value) {
+	if slices.Equal(oldTags, tags) && maps.Equal(old, metadata) {
+		return
+	}
+
+	data.pending = append(data.pending, Event{
+		Kind:        EventMetadataChanged,
+		ID:          id,
+		Name:        name,
+		Parents:     data.parentNames(id),
+		Tags:        slices.Clone(tags),
+		OldTags:     slices.Clone(oldTags),
+		Metadata:    maps.Clone(metadata),
+		OldMetadata: maps.Clone(old),
+	})
+}
+
+// Record what changed between old and the data as it is now. Removals come
+// first, with entries before their sub-entries.
+func (data *indexData) emitDifferences(old *indexData) {
+	for id := range old.walk(ACID{}) {
+		if _, err := data.name(id); err != nil {
+			oldName, _ := old.name(id)
+			data.pending = append(data.pending, Event{Kind: EventRemoved, ID: id, Name: oldName, Parents: old.parentNames(id)})
+		}
+	}
+
+	for id := range data.walk(ACID{}) {
+		name, _ := data.name(id)
+		oldName, err := old.name(id)
+		if err != nil {
+			data.emit(EventAdded, id, name)
+			continue
+		}
+
+		data.emitRename(id, oldName, name)
+
+		oldTags, oldMetadata := old.values(id)
+		tags, metadata := data.values(id)
+		data.emitMetadata(id, name, oldTags, tags, oldMetadata, metadata)
+	}
+}
+
+// Get the names of the area, category and, for a sub-entry, entry an area,
+// category or entry is in.
+func (data *indexData) parentNames(id ACID) (names []string) {
+	parents := []ACID{id.AreaID(), id.CategoryID(), id.EntryID()}[:id.Level()-1]
+	for _, parent := range parents {
+		name, _ := data.name(parent)
+		names = append(names, name)
+	}
+
+	return
+}
+
+// Get the tags and metadata set directly on an area, category or entry.
+func (data *indexData) values(id ACID) (tags []string, metadata map[string]Value) {
+	switch id.Level() {
+	case LevelArea:
+		return nil, data.areas[id.Area].metadata
+	case LevelCategory:
+		return nil, data.areas[id.Area].categories[id.Category].metadata
+	}
+
+	entry := data.entries[id.String()]
+	return entry.Tags, entry.Metadata
+}
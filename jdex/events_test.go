@@ -0,0 +1,193 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Index_Observe(t *testing.T) {
+	index, _ := jdex.NewIndex()
+
+	var events []string
+	stop := index.Observe(func(event jdex.Event) {
+		events = append(events, event.Kind.String()+" "+event.ID.String()+" "+event.Name)
+
+		// observers may read the index
+		_, err := index.Name(jdex.MustParseACID("00.00"))
+		assert.NoError(t, err)
+	})
+
+	id := jdex.MustParseACID("11.01")
+	assert.NoError(t, index.PutArea(id, "Finance"))
+	assert.NoError(t, index.PutArea(id, "Finance"))
+	assert.NoError(t, index.PutCategory(id, "Banking"))
+	assert.NoError(t, index.PutEntry(jdex.Entry{ID: id, Name: "Savings"}))
+	assert.NoError(t, index.PutEntry(jdex.Entry{ID: id, Name: "Savings"}))
+	assert.NoError(t, index.PutEntry(jdex.Entry{ID: id, Name: "Savings", Tags: []string{"money"}}))
+	assert.NoError(t, index.PutEntry(jdex.Entry{ID: id, Name: "Saver", Tags: []string{"money"}}))
+	assert.NoError(t, index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.01+001"), Name: "Statement"}))
+	assert.NoError(t, index.PutCategoryMetadata(id, map[string]jdex.Value{"Bank": jdex.StringValue("Kiwibank")}))
+	_, err := index.RenumberEntry(id, jdex.MustParseACID("11.02"))
+	assert.NoError(t, err)
+
+	stop()
+	assert.NoError(t, index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.03"), Name: "Ignored"}))
+
+	assert.Equal(t, []string{
+		"added 10-19 Finance",
+		"added 11 Banking",
+		"added 11.01 Savings",
+		"metadata changed 11.01 Savings",
+		"renamed 11.01 Saver",
+		"added 11.01+001 Statement",
+		"metadata changed 11 Banking",
+		"added 11.02 Saver",
+		"added 11.02+001 Statement",
+		"removed 11.01 Saver",
+		"removed 11.01+001 Statement",
+	}, events)
+}
+
+func Test_Index_Observe_Parents(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	id := jdex.MustParseACID("11.01")
+	index.PutArea(id, "Finance")
+	index.PutCategory(id, "Banking")
+	index.PutEntry(jdex.Entry{ID: id, Name: "Savings"})
+	index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.01+001"), Name: "Statement"})
+
+	var parents [][]string
+	stop := index.Observe(func(event jdex.Event) {
+		parents = append(parents, event.Parents)
+	})
+	defer stop()
+
+	assert.NoError(t, index.PutArea(id, "Money"))
+	_, err := index.RemoveEntry(id)
+	assert.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		nil,
+		{"Money", "Banking"},
+		{"Money", "Banking", "Savings"},
+	}, parents)
+}
+
+func Test_Index_Events(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	events, stop := index.Events(8)
+
+	id := jdex.MustParseACID("11.01")
+	index.PutArea(id, "Finance")
+	index.PutArea(id, "Money")
+
+	event := <-events
+	assert.Equal(t, jdex.Event{Kind: jdex.EventAdded, ID: id.AreaID(), Name: "Finance"}, event)
+
+	event = <-events
+	assert.Equal(t, jdex.Event{Kind: jdex.EventRenamed, ID: id.AreaID(), Name: "Money", OldName: "Finance"}, event)
+
+	index.PutCategory(id, "Banking")
+	index.PutEntry(jdex.Entry{ID: id, Name: "Savings", Tags: []string{"money"}})
+	index.PutEntry(jdex.Entry{ID: id, Name: "Savings", Metadata: map[string]jdex.Value{"Bank": jdex.StringValue("ANZ")}})
+
+	<-events
+	<-events
+	event = <-events
+	assert.Equal(t, jdex.EventMetadataChanged, event.Kind)
+	assert.Equal(t, []string{"money"}, event.OldTags)
+	assert.Empty(t, event.Tags)
+	assert.Empty(t, event.OldMetadata)
+	assert.Equal(t, map[string]jdex.Value{"Bank": jdex.StringValue("ANZ")}, event.Metadata)
+
+	stop()
+	_, open := <-events
+	assert.False(t, open)
+}
+
+func Test_Index_Replace(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	id := jdex.MustParseACID("11.01")
+	index.PutArea(id, "Finance")
+	index.PutCategory(id, "Banking")
+	index.PutEntry(jdex.Entry{ID: id, Name: "Savings"})
+	index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.01+001"), Name: "Statement"})
+
+	other := index.Snapshot()
+	other.PutEntry(jdex.Entry{ID: id, Name: "Saver", Tags: []string{"money"}})
+	other.RemoveEntry(jdex.MustParseACID("11.01+001"))
+	other.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.02"), Name: "Cheque"})
+
+	var events []string
+	index.Observe(func(event jdex.Event) {
+		events = append(events, event.Kind.String()+" "+event.ID.String())
+	})
+
+	index.Replace(other)
+
+	assert.Equal(t, []string{
+		"removed 11.01+001",
+		"renamed 11.01",
+		"metadata changed 11.01",
+		"added 11.02",
+	}, events)
+
+	entry, err := index.Entry(id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Saver", entry.Name)
+
+	// the two stay separate afterwards
+	index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.03"), Name: "Term"})
+	_, err = other.Entry(jdex.MustParseACID("11.03"))
+	assert.Error(t, err)
+}
+
+func Test_Index_Events_StopUndrained(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	_, stop := index.Events(0)
+
+	written := make(chan struct{})
+	go func() {
+		index.PutArea(jdex.MustParseACID("11.01"), "Finance")
+		close(written)
+	}()
+
+	// give the change a moment to start waiting on the channel
+	time.Sleep(10 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+
+	for _, done := range []chan struct{}{stopped, written} {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("stopping an undrained subscription hung")
+		}
+	}
+
+	// the index can still be changed afterwards
+	assert.NoError(t, index.PutArea(jdex.MustParseACID("21.01"), "Work"))
+}
@@ -0,0 +1,275 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// IDScheme is the policy for which IDs an index allows and how new ones are
+// allocated. It is kept in the index header, as the `Scheme` metadata of the
+// system index entry.
+type IDScheme struct {
+	Base          int // 10 for digits only, or 36 to also allow A-Z.
+	CategoryWidth int // Characters in a category after the area, or 0 for any.
+	EntryWidth    int // Characters in an entry, or 0 for any.
+}
+
+var (
+	// Strict Johnny.Decimal: a one-digit category and a two-digit entry.
+	StrictScheme = IDScheme{Base: 10, CategoryWidth: 1, EntryWidth: 2}
+	// Johnny.Decimal widths, counting in base 36 once the digits run out.
+	Base36Scheme = IDScheme{Base: 36, CategoryWidth: 1, EntryWidth: 2}
+	// Any characters in ACIDCharset, any widths. Indexes from before schemes
+	// were kept use this.
+	ExtendedScheme = IDScheme{Base: 36}
+)
+
+var namedSchemes = map[string]IDScheme{
+	"strict":   StrictScheme,
+	"base36":   Base36Scheme,
+	"extended": ExtendedScheme,
+}
+
+var schemeBaseNames = map[int]string{
+	10: "decimal",
+	36: "base36",
+}
+
+// Metadata key of the system index entry the scheme is kept in.
+const SchemeMetadataKey = "Scheme"
+
+var ErrUnknownIDScheme = errors.New("unknown ID scheme")
+var ErrIDScheme = errors.New("ID does not fit the index's ID scheme")
+
+// Parse a scheme, either by name (`strict`, `base36` or `extended`) or as
+// `decimal:C:E` or `base36:C:E` for category and entry widths C and E.
+func ParseIDScheme(input string) (scheme IDScheme, err error) {
+	if scheme, ok := namedSchemes[strings.ToLower(input)]; ok {
+		return scheme, nil
+	}
+
+	parts := strings.Split(input, ":")
+	if len(parts) != 3 {
+		err = fmt.Errorf("%w: %q", ErrUnknownIDScheme, input)
+		return
+	}
+
+	for base, name := range schemeBaseNames {
+		if strings.EqualFold(parts[0], name) {
+			scheme.Base = base
+		}
+	}
+
+	var widthErrs [2]error
+	scheme.CategoryWidth, widthErrs[0] = strconv.Atoi(parts[1])
+	scheme.EntryWidth, widthErrs[1] = strconv.Atoi(parts[2])
+
+	if scheme.Base == 0 || errors.Join(widthErrs[:]...) != nil ||
+		scheme.CategoryWidth < 0 || scheme.EntryWidth < 0 {
+		err = fmt.Errorf("%w: %q", ErrUnknownIDScheme, input)
+		return IDScheme{}, err
+	}
+
+	return
+}
+
+// Returns the scheme's name, or its `base:C:E` form.
+func (scheme IDScheme) String() string {
+	for _, name := range []string{"strict", "base36", "extended"} {
+		if namedSchemes[name] == scheme {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("%s:%d:%d", schemeBaseNames[scheme.Base], scheme.CategoryWidth, scheme.EntryWidth)
+}
+
+// Checks an ID is valid in a scheme: that it is valid, and fits the scheme.
+// The system's protected entries, and the area and category they're in, fit
+// any scheme.
+func (id *ACID) ValidIn(scheme IDScheme) error {
+	if err := id.Valid(); err != nil {
+		return err
+	}
+
+	if isSystemID(*id) {
+		return nil
+	}
+
+	digits := ACIDCharset[:scheme.Base]
+	if !strings.ContainsRune(digits, rune(id.Area)) {
+		return fmt.Errorf("%w: %s: area %q is not a %s digit", ErrIDScheme, id.String(), id.Area, schemeBaseNames[scheme.Base])
+	}
+
+	parts := []struct {
+		name  string
+		value string
+		width int
+	}{
+		{"category", id.Category, scheme.CategoryWidth},
+		{"entry", id.Entry, scheme.EntryWidth},
+	}
+
+	for _, part := range parts {
+		if part.value == "" {
+			continue
+		}
+
+		if strings.Trim(part.value, digits) != "" {
+			return fmt.Errorf("%w: %s: %s %q has characters other than %s digits",
+				ErrIDScheme, id.String(), part.name, part.value, schemeBaseNames[scheme.Base])
+		}
+
+		if part.width > 0 && len(part.value) != part.width {
+			return fmt.Errorf("%w: %s: %s %q is not %d characters",
+				ErrIDScheme, id.String(), part.name, part.value, part.width)
+		}
+	}
+
+	return nil
+}
+
+// Whether an ID is one of the protected entries, or the area or category one
+// is in.
+func isSystemID(id ACID) bool {
+	for _, protected := range ProtectedACIDs {
+		system := MustParseACID(protected)
+		switch id.Level() {
+		case LevelArea:
+			if id == system.AreaID() {
+				return true
+			}
+		case LevelCategory:
+			if id == system.CategoryID() {
+				return true
+			}
+		case LevelEntry:
+			if id == system {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Get the ID scheme the index enforces.
+func (index *Index) Scheme() IDScheme {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.scheme
+}
+
+// Change the ID scheme the index enforces. Fails if any existing IDs don't fit
+// the new scheme.
+func (index *Index) SetScheme(scheme IDScheme) error {
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().setScheme(scheme)
+}
+
+func (data *indexData) setScheme(scheme IDScheme) error {
+	entry, err := data.entry(MustParseACID("00.00"))
+	if err != nil {
+		return err
+	}
+
+	entry.Metadata = maps.Clone(entry.Metadata)
+	if entry.Metadata == nil {
+		entry.Metadata = map[string]Value{}
+	}
+
+	entry.Metadata[SchemeMetadataKey] = StringValue(scheme.String())
+	return data.putEntry(entry)
+}
+
+// Picks up a change to the scheme from the system index entry, as long as the
+// index's IDs fit it.
+func (data *indexData) loadScheme(entry Entry) error {
+	scheme := ExtendedScheme
+	if value, ok := entry.Metadata[SchemeMetadataKey]; ok {
+		var err error
+		scheme, err = ParseIDScheme(value.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	if scheme == data.scheme {
+		return nil
+	}
+
+	var errs []error
+	for id := range data.walk(ACID{}) {
+		errs = append(errs, id.ValidIn(scheme))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	data.scheme = scheme
+	return nil
+}
+
+// Checks an ID is local and fits the index's scheme.
+func (data *indexData) validID(id ACID) error {
+	if err := id.ValidLocal(); err != nil {
+		return errors.Join(ErrInvalidID, err)
+	}
+
+	if err := id.ValidIn(data.scheme); err != nil {
+		return errors.Join(ErrInvalidID, err)
+	}
+
+	return nil
+}
+
+// Formats n as a part of an ID, in the scheme's base and padded to width.
+func (scheme IDScheme) format(n int, width int) string {
+	s := strings.ToUpper(strconv.FormatInt(int64(n), scheme.Base))
+	if len(s) < width {
+		s = strings.Repeat("0", width-len(s)) + s
+	}
+
+	return s
+}
+
+// Returns the base, width and highest number new IDs are allocated with.
+// Schemes without a fixed width allocate decimal IDs of the given width.
+func (scheme IDScheme) allocation(fixedWidth int, fallbackWidth int) (allocation IDScheme, width int, max int) {
+	allocation = scheme
+	width = fixedWidth
+	if width == 0 {
+		allocation.Base = 10
+		width = fallbackWidth
+	}
+
+	max = 1
+	for range width {
+		max *= allocation.Base
+	}
+
+	return allocation, width, max - 1
+}
@@ -0,0 +1,111 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex_test
+
+import (
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIDScheme(t *testing.T) {
+	for input, expected := range map[string]jdex.IDScheme{
+		"strict":      jdex.StrictScheme,
+		"base36":      jdex.Base36Scheme,
+		"extended":    jdex.ExtendedScheme,
+		"decimal:1:3": {Base: 10, CategoryWidth: 1, EntryWidth: 3},
+	} {
+		scheme, err := jdex.ParseIDScheme(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, scheme, input)
+		assert.Equal(t, input, scheme.String())
+	}
+
+	for _, input := range []string{"", "octal:1:2", "decimal:1", "decimal:x:2"} {
+		_, err := jdex.ParseIDScheme(input)
+		assert.ErrorIs(t, err, jdex.ErrUnknownIDScheme, input)
+	}
+}
+
+func TestACIDValidIn(t *testing.T) {
+	assert.NoError(t, validScheme("12.34", jdex.StrictScheme))
+	assert.ErrorIs(t, validScheme("1A.34", jdex.StrictScheme), jdex.ErrIDScheme)
+	assert.ErrorIs(t, validScheme("12.345", jdex.StrictScheme), jdex.ErrIDScheme)
+	assert.ErrorIs(t, validScheme("1AB.34", jdex.Base36Scheme), jdex.ErrIDScheme)
+
+	assert.NoError(t, validScheme("1A.3Z", jdex.Base36Scheme))
+	assert.NoError(t, validScheme("WFLX.10", jdex.ExtendedScheme))
+	assert.NoError(t, validScheme("00.00", jdex.IDScheme{Base: 10, CategoryWidth: 2, EntryWidth: 3}))
+	assert.ErrorIs(t, validScheme("00.ABC", jdex.StrictScheme), jdex.ErrIDScheme)
+	assert.ErrorIs(t, validScheme("00.01", jdex.IDScheme{Base: 10, CategoryWidth: 2, EntryWidth: 3}), jdex.ErrIDScheme)
+}
+
+func validScheme(input string, scheme jdex.IDScheme) error {
+	id := jdex.MustParseACID(input)
+	return id.ValidIn(scheme)
+}
+
+func Test_Index_Scheme_Enforced(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	assert.Equal(t, jdex.StrictScheme, index.Scheme())
+
+	err := index.PutArea(jdex.ACID{Area: 'A'}, "Letters")
+	assert.ErrorIs(t, err, jdex.ErrIDScheme)
+
+	id := jdex.MustParseACID("11.01")
+	index.PutArea(id, "Finance")
+	err = index.PutCategory(jdex.ACID{Area: '1', Category: "12"}, "Wide")
+	assert.ErrorIs(t, err, jdex.ErrIDScheme)
+
+	index.PutCategory(id, "Invoices")
+	index.PutEntry(jdex.Entry{ID: id, Name: "Acme"})
+
+	err = index.SetScheme(jdex.IDScheme{Base: 10, CategoryWidth: 1, EntryWidth: 3})
+	assert.ErrorIs(t, err, jdex.ErrIDScheme)
+	assert.Equal(t, jdex.StrictScheme, index.Scheme())
+
+	assert.NoError(t, index.SetScheme(jdex.ExtendedScheme))
+	assert.NoError(t, index.PutArea(jdex.ACID{Area: 'A'}, "Letters"))
+}
+
+func Test_Index_NextEntryID_Base36(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	index.SetScheme(jdex.Base36Scheme)
+
+	id := jdex.MustParseACID("11.09")
+	index.PutArea(id, "Finance")
+	index.PutCategory(id, "Invoices")
+	index.PutEntry(jdex.Entry{ID: id, Name: "Acme"})
+
+	next, err := index.NextEntryID(id.CategoryID())
+	assert.NoError(t, err)
+	assert.Equal(t, "11.0A", next.String())
+}
+
+func Test_Index_NextEntryID_FailFull(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	index.SetScheme(jdex.IDScheme{Base: 10, CategoryWidth: 1, EntryWidth: 1})
+
+	id := jdex.MustParseACID("11.9")
+	index.PutArea(id, "Finance")
+	index.PutCategory(id, "Invoices")
+	index.PutEntry(jdex.Entry{ID: id, Name: "Acme"})
+
+	_, err := index.NextEntryID(id.CategoryID())
+	assert.ErrorIs(t, err, jdex.ErrCategoryFull)
+}
@@ -18,34 +18,64 @@ package jdex
 
 import (
 	"errors"
+	"fmt"
 	"maps"
 	"slices"
+	"strconv"
+	"sync"
+	"time"
 )
 
 // Index is the entry database. It stores the entries, their names and
 // immediate metadata.
 //
 // This data is stored in "00.00 System Index" within the system.
+//
+// An index is safe for concurrent use. Snapshots of it can be taken cheaply,
+// and are left as they were when the index is changed afterwards.
 type Index struct {
+	mu     sync.RWMutex
+	data   *indexData
+	shared bool // Whether data is shared with a snapshot, so must be copied before it's changed.
+
+	notifyMu     sync.Mutex // Held while passing changes on, so they arrive in order.
+	observersMu  sync.Mutex
+	observers    []registeredObserver
+	nextObserver int
+}
+
+type indexData struct {
 	entries map[string]Entry
 	areas   map[byte]indexArea
+	subs    map[string]map[string]bool
+	tags    map[string]map[string]bool
+	notes   map[string][]ACID
+	scheme  IDScheme
+	pending []Event // Changes made since the index was locked for writing.
 }
 
 // Represents a single entry in the system.
 type Entry struct {
-	ID       ACID              // Entry's AC.ID.
-	Name     string            // Entry's name.
-	Metadata map[string]string // Entry's immediate metadata.
+	ID       ACID             // Entry's AC.ID.
+	Name     string           // Entry's name.
+	Tags     []string         // Entry's tags, sorted.
+	Metadata map[string]Value // Entry's immediate metadata.
+	Created  time.Time        // When the entry was added.
+	Modified time.Time        // When the entry was last changed.
 }
 
 type indexArea struct {
 	name       string
+	metadata   map[string]Value
+	schema     Schema
 	categories map[string]indexCategory
 }
 
 type indexCategory struct {
-	name    string
-	entries map[string]bool
+	name     string
+	metadata map[string]Value
+	schema   Schema
+	entries  map[string]bool
 }
 
 // These AC.IDs are reserved by the system. Users should not edit these
@@ -54,6 +84,11 @@ var ProtectedACIDs = []string{
 	"00.00", // system index
 }
 
+// Entry timestamps are kept to the second, as that's what the index stores.
+var now = func() time.Time {
+	return time.Now().Truncate(time.Second)
+}
+
 var ErrInvalidID = errors.New("entry ID is invalid")
 var ErrProtectedID = errors.New("entry ID is used by the system")
 
@@ -61,37 +96,130 @@ var ErrEntryNotFound = errors.New("entry does not exist")
 var ErrCategoryNotFound = errors.New("category does not exist")
 var ErrAreaNotFound = errors.New("area does not exist")
 
+var ErrCategoryFull = errors.New("category has no free entry IDs")
+var ErrEntryFull = errors.New("entry has no free sub-entry IDs")
+var ErrAreaFull = errors.New("area has no free category IDs")
+
 var ErrUnknownFormat = errors.New("unknown format")
 
 // Creates a new index
-func NewIndex() (Index, error) {
-	index := Index{
+func NewIndex() (*Index, error) {
+	index := &Index{data: &indexData{
 		entries: make(map[string]Entry),
 		areas:   make(map[byte]indexArea),
-	}
+		subs:    make(map[string]map[string]bool),
+		tags:    make(map[string]map[string]bool),
+		notes:   make(map[string][]ACID),
+		scheme:  ExtendedScheme,
+	}}
 
 	indexID := MustParseACID("00.00")
 
 	index.PutArea(indexID, "System")
 	index.PutCategory(indexID, "Index")
-	index.PutEntry(Entry{
+	index.LoadEntry(Entry{
 		ID:   indexID,
 		Name: "System Index",
-		Metadata: map[string]string{
-			"Format": "jdex",
+		Metadata: map[string]Value{
+			"Format":          StringValue("jdex"),
+			SchemeMetadataKey: StringValue(StrictScheme.String()),
 		},
 	})
 
 	return index, nil
 }
 
+// Take a snapshot of the index. The snapshot keeps the index as it is now,
+// however it's changed afterwards, and can itself be changed without
+// affecting the index. The index is only copied once either is changed.
+func (index *Index) Snapshot() *Index {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	index.shared = true
+	return &Index{data: index.data, shared: true}
+}
+
+// Replace everything in the index with what's in another, such as an index
+// read back from a file. Observers are told about the differences.
+func (index *Index) Replace(other *Index) {
+	replacement := other.Snapshot().data
+
+	index.mu.Lock()
+	defer index.unlock()
+
+	old := index.data
+	index.data = replacement
+	index.shared = true
+	index.writable().emitDifferences(old)
+}
+
+// Get the data to change, copying it first if a snapshot shares it. The
+// index must be locked for writing.
+func (index *Index) writable() *indexData {
+	if index.shared {
+		index.data = index.data.clone()
+		index.shared = false
+	}
+
+	return index.data
+}
+
+// Copy the data, down to the maps which are changed in place. Entries,
+// metadata and schemas are replaced rather than changed, so are shared.
+func (data *indexData) clone() *indexData {
+	clone := &indexData{
+		entries: maps.Clone(data.entries),
+		areas:   make(map[byte]indexArea, len(data.areas)),
+		subs:    make(map[string]map[string]bool, len(data.subs)),
+		tags:    make(map[string]map[string]bool, len(data.tags)),
+		notes:   maps.Clone(data.notes),
+		scheme:  data.scheme,
+	}
+
+	for areaKey, area := range data.areas {
+		area.categories = maps.Clone(area.categories)
+		for categoryKey, category := range area.categories {
+			category.entries = maps.Clone(category.entries)
+			area.categories[categoryKey] = category
+		}
+
+		clone.areas[areaKey] = area
+	}
+
+	for key, subs := range data.subs {
+		clone.subs[key] = maps.Clone(subs)
+	}
+
+	for tag, tagged := range data.tags {
+		clone.tags[tag] = maps.Clone(tagged)
+	}
+
+	return clone
+}
+
 func (index *Index) Entry(id ACID) (entry Entry, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	entry, err = index.data.entry(id)
+	return entry.clone(), err
+}
+
+// Copy an entry, so changing its tags or metadata doesn't change the index.
+func (entry Entry) clone() Entry {
+	entry.Tags = slices.Clone(entry.Tags)
+	entry.Metadata = maps.Clone(entry.Metadata)
+	return entry
+}
+
+func (data *indexData) entry(id ACID) (entry Entry, err error) {
 	if err = id.ValidLocal(); err != nil {
 		err = errors.Join(ErrInvalidID, err)
 		return
 	}
 
-	entry, ok := index.entries[id.String()]
+	entry, ok := data.entries[id.String()]
 	if !ok {
 		err = ErrEntryNotFound
 		return
@@ -100,55 +228,84 @@ func (index *Index) Entry(id ACID) (entry Entry, err error) {
 	return
 }
 
-func (index *Index) AreaIndexes() (ids []ACID) {
-	ids = make([]ACID, len(index.areas))
-	for n, k := range slices.Sorted(maps.Keys(index.areas)) {
-		ids[n] = ACID{Area: k}
-	}
+// Get the IDs of every area, in order.
+func (index *Index) AreaIndexes() []ACID {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
 
-	return
+	return index.data.children(ACID{})
 }
 
+// Get the categories of an area, in order.
 func (index *Index) Categories(id ACID) (ids []ACID, ok bool) {
-	area, ok := index.areas[id.Area]
-	if !ok {
-		return
-	}
+	index.mu.RLock()
+	defer index.mu.RUnlock()
 
-	ids = make([]ACID, len(area.categories))
-	for n, k := range slices.Sorted(maps.Keys(area.categories)) {
-		ids[n] = ACID{Area: id.Area, Category: k}
+	if _, ok = index.data.areas[id.Area]; !ok {
+		return
 	}
 
-	return
+	return index.data.children(id.AreaID()), true
 }
 
+// Get the entries of a category, in order.
 func (index *Index) Entries(id ACID) (ids []ACID, ok bool) {
-	area, ok := index.areas[id.Area]
-	if !ok {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	if _, ok = index.data.areas[id.Area].categories[id.Category]; !ok {
 		return
 	}
 
-	category, ok := area.categories[id.Category]
-	if !ok {
+	return index.data.children(id.CategoryID()), true
+}
+
+// Get the sub-entries of an entry.
+func (index *Index) SubEntries(id ACID) (ids []ACID, ok bool) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	if _, ok = index.data.entries[id.String()]; !ok {
 		return
 	}
 
-	ids = make([]ACID, len(category.entries))
-	for n, k := range slices.Sorted(maps.Keys(category.entries)) {
-		ids[n] = index.entries[k].ID
+	return index.data.children(id.EntryID()), true
+}
+
+// Get the name of an area, category or entry.
+func (index *Index) Name(id ACID) (name string, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.name(id)
+}
+
+func (data *indexData) name(id ACID) (name string, err error) {
+	switch id.Level() {
+	case LevelArea:
+		return data.areaName(id)
+	case LevelCategory:
+		return data.categoryName(id)
 	}
 
-	return
+	entry, err := data.entry(id)
+	return entry.Name, err
 }
 
 func (index *Index) AreaName(id ACID) (name string, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.areaName(id)
+}
+
+func (data *indexData) areaName(id ACID) (name string, err error) {
 	if err = id.ValidLocal(); err != nil {
 		err = errors.Join(ErrInvalidID, err)
 		return
 	}
 
-	area, ok := index.areas[id.Area]
+	area, ok := data.areas[id.Area]
 	if !ok {
 		err = ErrAreaNotFound
 		return
@@ -158,12 +315,19 @@ func (index *Index) AreaName(id ACID) (name string, err error) {
 }
 
 func (index *Index) CategoryName(id ACID) (name string, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.categoryName(id)
+}
+
+func (data *indexData) categoryName(id ACID) (name string, err error) {
 	if err = id.ValidLocal(); err != nil {
 		err = errors.Join(ErrInvalidID, err)
 		return
 	}
 
-	area, ok := index.areas[id.Area]
+	area, ok := data.areas[id.Area]
 	if !ok {
 		err = ErrAreaNotFound
 		return
@@ -179,30 +343,46 @@ func (index *Index) CategoryName(id ACID) (name string, err error) {
 }
 
 func (index *Index) PutArea(id ACID, name string) error {
-	if err := id.ValidLocal(); err != nil {
-		return errors.Join(ErrInvalidID, err)
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().putArea(id, name)
+}
+
+func (data *indexData) putArea(id ACID, name string) error {
+	if err := data.validID(id); err != nil {
+		return err
 	}
 
-	area, ok := index.areas[id.Area]
+	area, ok := data.areas[id.Area]
 	if !ok {
 		area = indexArea{
 			name:       name,
 			categories: make(map[string]indexCategory),
 		}
+		data.emit(EventAdded, id.AreaID(), name)
 	} else {
+		data.emitRename(id.AreaID(), area.name, name)
 		area.name = name
 	}
 
-	index.areas[id.Area] = area
+	data.areas[id.Area] = area
 	return nil
 }
 
 func (index *Index) PutCategory(id ACID, name string) error {
-	if err := id.ValidLocal(); err != nil {
-		return errors.Join(ErrInvalidID, err)
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().putCategory(id, name)
+}
+
+func (data *indexData) putCategory(id ACID, name string) error {
+	if err := data.validID(id); err != nil {
+		return err
 	}
 
-	area, ok := index.areas[id.Area]
+	area, ok := data.areas[id.Area]
 	if !ok {
 		return ErrAreaNotFound
 	}
@@ -213,7 +393,9 @@ func (index *Index) PutCategory(id ACID, name string) error {
 			name:    name,
 			entries: map[string]bool{},
 		}
+		data.emit(EventAdded, id.CategoryID(), name)
 	} else {
+		data.emitRename(id.CategoryID(), category.name, name)
 		category.name = name
 	}
 
@@ -221,28 +403,228 @@ func (index *Index) PutCategory(id ACID, name string) error {
 	return nil
 }
 
+// Add or replace an entry. Its metadata is checked against the category's
+// schema, with defaults filled in, and its timestamps are updated.
 func (index *Index) PutEntry(entry Entry) (err error) {
-	id := entry.ID
+	index.mu.Lock()
+	defer index.unlock()
 
-	if err = id.ValidLocal(); err != nil {
-		return errors.Join(ErrInvalidID, err)
+	return index.writable().putEntry(entry)
+}
+
+func (data *indexData) putEntry(entry Entry) (err error) {
+	if err = data.validID(entry.ID); err != nil {
+		return
 	}
 
-	area, ok := index.areas[id.Area]
+	entry.Metadata, err = data.conform(entry)
+	if err != nil {
+		return
+	}
+
+	entry.Modified = now()
+	if entry.Created.IsZero() {
+		entry.Created = entry.Modified
+		if old, ok := data.entries[entry.ID.String()]; ok && !old.Created.IsZero() {
+			entry.Created = old.Created
+		}
+	}
+
+	return data.loadEntry(entry)
+}
+
+// Add or replace an entry as-is, without checking it against its schema.
+// This is meant for reading existing indexes, see Index.Validate for finding
+// entries which don't conform.
+func (index *Index) LoadEntry(entry Entry) (err error) {
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().loadEntry(entry)
+}
+
+// Checks an entry can be kept under an ID: that the ID is valid and the area,
+// category and, for a sub-entry, entry it goes in are there.
+func (data *indexData) checkEntryID(id ACID) error {
+	if err := data.validID(id); err != nil {
+		return err
+	}
+
+	if level := id.Level(); level != LevelEntry && level != LevelSub {
+		return fmt.Errorf("%w: %q is not an entry", ErrInvalidID, id.String())
+	}
+
+	if parentID := id.EntryID(); id.Sub != "" {
+		if _, ok := data.entries[parentID.String()]; !ok {
+			return fmt.Errorf("%w: %q has no parent entry", ErrEntryNotFound, id.String())
+		}
+	}
+
+	area, ok := data.areas[id.Area]
 	if !ok {
-		err = ErrAreaNotFound
+		return ErrAreaNotFound
+	}
+
+	if _, ok := area.categories[id.Category]; !ok {
+		return ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+func (data *indexData) loadEntry(entry Entry) (err error) {
+	id := entry.ID
+
+	if err = data.checkEntryID(id); err != nil {
 		return
 	}
 
-	category, ok := area.categories[id.Category]
+	if IsProtectedACID(id) {
+		if err = data.loadScheme(entry); err != nil {
+			return
+		}
+	}
+
+	parentID := id.EntryID()
+	category := data.areas[id.Area].categories[id.Category]
+
+	entry.Tags, err = NormaliseTags(entry.Tags)
+	if err != nil {
+		return
+	}
+
+	// the caller keeps its map, so changing it later can't reach the index
+	entry.Metadata = maps.Clone(entry.Metadata)
+
+	if old, ok := data.entries[id.String()]; ok {
+		data.untagEntry(old)
+		data.emitRename(id, old.Name, entry.Name)
+		data.emitMetadata(id, entry.Name, old.Tags, entry.Tags, old.Metadata, entry.Metadata)
+	} else {
+		data.emit(EventAdded, id, entry.Name)
+	}
+
+	if id.Sub != "" {
+		if data.subs[parentID.String()] == nil {
+			data.subs[parentID.String()] = map[string]bool{}
+		}
+		data.subs[parentID.String()][id.String()] = true
+	} else {
+		category.entries[id.String()] = true
+	}
+
+	data.entries[id.String()] = entry
+	data.tagEntry(entry)
+
+	return
+}
+
+// Get the ID after the highest entry in a category, going by the index's ID
+// scheme.
+func (index *Index) NextEntryID(id ACID) (next ACID, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.nextEntryID(id)
+}
+
+func (data *indexData) nextEntryID(id ACID) (next ACID, err error) {
+	if _, ok := data.areas[id.Area].categories[id.Category]; !ok {
+		_, err = data.categoryName(id)
+		return
+	}
+
+	entries := data.children(id.CategoryID())
+	scheme, width, max := data.scheme.allocation(data.scheme.EntryWidth, 2)
+
+	n := 1
+	if len(entries) > 0 {
+		last, convErr := strconv.ParseInt(entries[len(entries)-1].Entry, scheme.Base, 64)
+		if convErr != nil {
+			err = fmt.Errorf("%w: %q is not numbered", ErrCategoryFull, entries[len(entries)-1].String())
+			return
+		}
+
+		n = int(last) + 1
+	}
+
+	if n > max {
+		err = ErrCategoryFull
+		return
+	}
+
+	next = ACID{
+		Area:     id.Area,
+		Category: id.Category,
+		Entry:    scheme.format(n, width),
+	}
+	return
+}
+
+// Get the ID after the highest numbered sub-entry of an entry, such as
+// `11.01+001`. Sub-entries which aren't numbered, such as `11.01+VLD`, are
+// skipped over.
+func (index *Index) NextSubID(id ACID) (next ACID, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.nextSubID(id)
+}
+
+func (data *indexData) nextSubID(id ACID) (next ACID, err error) {
+	if id.Level() != LevelEntry {
+		err = fmt.Errorf("%w: %q is not an entry", ErrInvalidID, id.String())
+		return
+	}
+
+	if _, err = data.entry(id); err != nil {
+		return
+	}
+
+	n := 1
+	for _, sub := range data.children(id) {
+		last, convErr := strconv.Atoi(sub.Sub)
+		if convErr == nil && last >= n {
+			n = last + 1
+		}
+	}
+
+	if n > 999 {
+		err = ErrEntryFull
+		return
+	}
+
+	next = id.EntryID()
+	next.Sub = fmt.Sprintf("%03d", n)
+	return
+}
+
+// Get the first free category ID in an area, going by the index's ID scheme.
+// The area's first category, such as 10, is left for managing the area
+// itself.
+func (index *Index) NextCategoryID(id ACID) (next ACID, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.nextCategoryID(id)
+}
+
+func (data *indexData) nextCategoryID(id ACID) (next ACID, err error) {
+	area, ok := data.areas[id.Area]
 	if !ok {
-		err = ErrCategoryNotFound
+		_, err = data.areaName(id)
 		return
 	}
 
-	category.entries[id.String()] = true
-	index.entries[id.String()] = entry
+	scheme, width, max := data.scheme.allocation(data.scheme.CategoryWidth, 1)
+	for n := 1; n <= max; n++ {
+		category := scheme.format(n, width)
+		if _, ok := area.categories[category]; !ok {
+			return ACID{Area: id.Area, Category: category}, nil
+		}
+	}
 
+	err = ErrAreaFull
 	return
 }
 
@@ -18,6 +18,7 @@ package jdex_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/itisrazza/rzjd/jdex"
 	"github.com/stretchr/testify/assert"
@@ -49,8 +50,9 @@ func Test_NewIndex_HasSystemIndex(t *testing.T) {
 			Entry:    "00",
 		},
 		Name: "System Index",
-		Metadata: map[string]string{
-			"Format": "jdex",
+		Metadata: map[string]jdex.Value{
+			"Format": jdex.StringValue("jdex"),
+			"Scheme": jdex.StringValue("strict"),
 		},
 	}, entry)
 
@@ -89,3 +91,78 @@ func Test_Index_PutEntry_FailNoCategory(t *testing.T) {
 
 	assert.ErrorIs(t, err, jdex.ErrCategoryNotFound)
 }
+
+func Test_Index_PutEntry_Timestamps(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	id := jdex.MustParseACID("11.11")
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	index.PutArea(id, "")
+	index.PutCategory(id, "")
+	index.LoadEntry(jdex.Entry{ID: id, Created: created, Modified: created})
+
+	before := time.Now().Add(-time.Second)
+	err := index.PutEntry(jdex.Entry{ID: id, Name: "Renamed"})
+	assert.NoError(t, err)
+
+	entry, _ := index.Entry(id)
+	assert.Equal(t, created, entry.Created)
+	assert.True(t, entry.Modified.After(before))
+}
+
+func Test_Index_Select(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	for _, input := range []string{"11.01", "11.10", "11.02", "12.01"} {
+		id := jdex.MustParseACID(input)
+		index.PutArea(id, "")
+		index.PutCategory(id, "")
+		index.PutEntry(jdex.Entry{ID: id})
+	}
+
+	selector, _ := jdex.ParseACIDSelector("11.01-11.10")
+	assert.Equal(t, []jdex.ACID{
+		jdex.MustParseACID("11.01"),
+		jdex.MustParseACID("11.02"),
+		jdex.MustParseACID("11.10"),
+	}, index.Select(selector))
+
+	selector, _ = jdex.ParseACIDSelector("1*.01")
+	assert.Equal(t, []jdex.ACID{
+		jdex.MustParseACID("11.01"),
+		jdex.MustParseACID("12.01"),
+	}, index.Select(selector))
+}
+
+func Test_Index_Name(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	id := jdex.MustParseACID("11.01")
+	index.PutArea(id, "Finance")
+	index.PutCategory(id, "Invoices")
+	index.PutEntry(jdex.Entry{ID: id, Name: "Acme"})
+
+	for input, expected := range map[string]string{"10-19": "Finance", "11": "Invoices", "11.01": "Acme"} {
+		id, _, _ := jdex.ParseID(input)
+		name, err := index.Name(id)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, name, input)
+	}
+
+	err := index.PutEntry(jdex.Entry{ID: id.CategoryID(), Name: "Invoices"})
+	assert.ErrorIs(t, err, jdex.ErrInvalidID)
+}
+
+func Test_Index_NextCategoryID(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	index.PutArea(jdex.ACID{Area: '1'}, "Finance")
+
+	next, err := index.NextCategoryID(jdex.ACID{Area: '1'})
+	assert.NoError(t, err)
+	assert.Equal(t, "11", next.String())
+
+	index.PutCategory(next, "Invoices")
+	next, _ = index.NextCategoryID(jdex.ACID{Area: '1'})
+	assert.Equal(t, "12", next.String())
+
+	_, err = index.NextCategoryID(jdex.ACID{Area: '2'})
+	assert.ErrorIs(t, err, jdex.ErrAreaNotFound)
+}
@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/itisrazza/rzjd/jdex"
@@ -35,6 +37,8 @@ type readContext struct {
 
 	lastID    jdex.ACID
 	lastEntry jdex.Entry
+	lastText  string // Entry's line after its ID, before tags were split off.
+	tagList   bool   // Whether the entry's tags have been given as a list.
 }
 
 var ErrParse = errors.New("failed to parse jdex")
@@ -46,9 +50,11 @@ var commentMultilineEndRegex = regexp.MustCompile(`^.*\*/`)
 var areaRegex = regexp.MustCompile(`^([A-Z0-9]0-[A-Z0-9]9)\s+(.+)$`)
 var categoryRegex = regexp.MustCompile(`^([A-Z0-9]+)\s+(.+)$`)
 var entryRegex = regexp.MustCompile(`^([A-Z0-9\.\+]+)?\s+(.+)$`)
-var metadataRegex = regexp.MustCompile(`^-\s*(.+?)\s*:\s*(.+?)\s*$`)
+var schemaRegex = regexp.MustCompile(`^%\s*(.+?)\s*:\s*(.*?)\s*$`)
+var metadataRegex = regexp.MustCompile(`^-\s*(.+?)\s*:\s*(.*?)\s*$`)
+var metadataTypeRegex = regexp.MustCompile(`^(.+?)\s*\((\w+)\)$`)
 
-func Read(r io.Reader) (index jdex.Index, err error) {
+func Read(r io.Reader) (index *jdex.Index, err error) {
 	index, err = jdex.NewIndex()
 	if err != nil {
 		return
@@ -58,7 +64,7 @@ func Read(r io.Reader) (index jdex.Index, err error) {
 	scanner.Split(bufio.ScanLines)
 
 	ctx := readContext{
-		index:            &index,
+		index:            index,
 		multilineComment: false,
 		lineNumber:       0,
 	}
@@ -126,6 +132,10 @@ func readProcessLine(line string, ctx *readContext) (err error) {
 		return readProcessCategoryLine(line, ctx)
 	}
 
+	if schemaRegex.MatchString(line) {
+		return readProcessSchemaLine(line, ctx)
+	}
+
 	if entryRegex.MatchString(line) {
 		return readProcessEntryLine(line, ctx)
 	}
@@ -137,14 +147,31 @@ func readProcessLine(line string, ctx *readContext) (err error) {
 	panic("unimplemented line kind")
 }
 
+// Parse an ID, checking it is valid in the scheme read so far.
+func readID(text string, ctx *readContext) (id jdex.ACID, level jdex.Level, err error) {
+	id, level, err = jdex.ParseID(text)
+	if err == nil {
+		err = id.ValidIn(ctx.index.Scheme())
+	}
+
+	return
+}
+
 func readProcessAreaLine(line string, ctx *readContext) error {
 	matches := areaRegex.FindStringSubmatch(line)
 
-	// FIXME: add some more guardrails around ID indexing like that
-	id := jdex.ACID{Area: matches[1][0]}
+	id, level, err := readID(matches[1], ctx)
+	if err != nil {
+		return err
+	}
+
+	if level != jdex.LevelArea {
+		return fmt.Errorf("%q is not an area", matches[1])
+	}
+
 	name := matches[2]
 
-	err := ctx.index.PutArea(id, name)
+	err = ctx.index.PutArea(id, name)
 	if err != nil {
 		return err
 	}
@@ -156,11 +183,15 @@ func readProcessAreaLine(line string, ctx *readContext) error {
 func readProcessCategoryLine(line string, ctx *readContext) error {
 	matches := categoryRegex.FindStringSubmatch(line)
 
-	// FIXME: add some more guardrails around ID indexing like that
-	id := jdex.ACID{
-		Area:     matches[1][0],
-		Category: matches[1][1:],
+	id, level, err := readID(matches[1], ctx)
+	if err != nil {
+		return err
+	}
+
+	if level != jdex.LevelCategory {
+		return fmt.Errorf("%q is not a category", matches[1])
 	}
+
 	name := matches[2]
 
 	if id.Area != ctx.lastID.Area {
@@ -170,7 +201,7 @@ func readProcessCategoryLine(line string, ctx *readContext) error {
 		)
 	}
 
-	err := ctx.index.PutCategory(id, name)
+	err = ctx.index.PutCategory(id, name)
 	if err != nil {
 		return err
 	}
@@ -182,7 +213,7 @@ func readProcessCategoryLine(line string, ctx *readContext) error {
 func readProcessEntryLine(line string, ctx *readContext) error {
 	matches := entryRegex.FindStringSubmatch(line)
 
-	id, err := jdex.ParseACID(matches[1])
+	id, _, err := readID(matches[1], ctx)
 	if err != nil {
 		return err
 	}
@@ -194,38 +225,264 @@ func readProcessEntryLine(line string, ctx *readContext) error {
 		)
 	}
 
+	if id.Sub != "" && id.Entry != ctx.lastID.Entry {
+		return fmt.Errorf("sub-entry %q is orphaned in %q",
+			matches[1],
+			ctx.lastID.String(),
+		)
+	}
+
+	name, tags := readSplitTags(matches[2])
 	entry := jdex.Entry{
 		ID:       id,
-		Name:     matches[2],
-		Metadata: make(map[string]string),
+		Name:     name,
+		Tags:     tags,
+		Metadata: make(map[string]jdex.Value),
 	}
 
-	err = ctx.index.PutEntry(entry)
+	err = ctx.index.LoadEntry(entry)
 	if err != nil {
 		return err
 	}
 
 	ctx.lastID = id
 	ctx.lastEntry = entry
+	ctx.lastText = matches[2]
+	ctx.tagList = false
 	return nil
 }
 
 func readProcessMetadataLine(line string, ctx *readContext) error {
-	if ctx.lastID != ctx.lastEntry.ID {
-		return errors.New("metadata can only be added to entries")
+	matches := metadataRegex.FindStringSubmatch(line)
+
+	key, typeName := readSplitMetadataType(matches[1])
+	value, err := readMetadataValue(typeName, matches[2])
+	if err != nil {
+		return err
 	}
 
-	matches := metadataRegex.FindStringSubmatch(line)
+	switch {
+	case ctx.lastID.Entry != "" && key == "Tags":
+		// tags given as a list, as older indexes kept them, mean what
+		// follows the ID is all name
+		if !ctx.tagList {
+			ctx.lastEntry.Name = ctx.lastText
+			ctx.lastEntry.Tags = nil
+			ctx.tagList = true
+		}
+
+		for _, tag := range strings.Split(value.String(), ",") {
+			tag = strings.Join(strings.Fields(tag), "-")
+			if tag != "" {
+				ctx.lastEntry.Tags = append(ctx.lastEntry.Tags, tag)
+			}
+		}
+		return ctx.index.LoadEntry(ctx.lastEntry)
+	case ctx.lastID.Entry != "" && isTimestampKey(key) && typeName == "" && value.Type == jdex.TypeDateTime:
+		// anything else under these keys is the user's own metadata
+		t, _ := value.Time()
+		if key == "Created" {
+			ctx.lastEntry.Created = t
+		} else {
+			ctx.lastEntry.Modified = t
+		}
+		return ctx.index.LoadEntry(ctx.lastEntry)
+	case ctx.lastID.Entry != "":
+		ctx.lastEntry.Metadata[key] = value
+		return ctx.index.LoadEntry(ctx.lastEntry)
+	case ctx.lastID.Category != "":
+		metadata, err := ctx.index.CategoryMetadata(ctx.lastID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.index.PutCategoryMetadata(ctx.lastID, withMetadata(metadata, key, value))
+	default:
+		metadata, err := ctx.index.AreaMetadata(ctx.lastID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.index.PutAreaMetadata(ctx.lastID, withMetadata(metadata, key, value))
+	}
+}
+
+// Whether an entry's metadata line might be one of its timestamps. Those are
+// written without a type, so metadata under the same keys is written with
+// one.
+func isTimestampKey(key string) bool {
+	return key == "Created" || key == "Modified"
+}
+
+// Splits `#tag` tokens off the end of an entry's name. Only tokens written
+// exactly as tags are split off, so names such as `Issue #42` or `Meeting #1.`
+// are kept whole.
+func readSplitTags(text string) (name string, tags []string) {
+	name = text
+	for {
+		i := strings.LastIndexAny(name, " \t")
+		if i < 0 {
+			break
+		}
+
+		token := name[i+1:]
+		tag, err := jdex.NormaliseTag(token)
+		if err != nil || token != "#"+tag {
+			break
+		}
+
+		tags = append(tags, tag)
+		name = strings.TrimRight(name[:i], " \t")
+	}
+
+	slices.Reverse(tags)
+	return
+}
+
+func withMetadata(metadata map[string]jdex.Value, key string, value jdex.Value) map[string]jdex.Value {
+	if metadata == nil {
+		metadata = map[string]jdex.Value{}
+	}
 
-	key := matches[1]
-	value := matches[2]
+	metadata[key] = value
+	return metadata
+}
+
+func readProcessSchemaLine(line string, ctx *readContext) error {
+	if ctx.lastID.Entry != "" {
+		return errors.New("schemas can only be declared on areas and categories")
+	}
 
-	ctx.lastEntry.Metadata[key] = value
+	matches := schemaRegex.FindStringSubmatch(line)
 
-	err := ctx.index.PutEntry(ctx.lastEntry)
+	field, err := readSchemaField(matches[1], matches[2])
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if ctx.lastID.Category == "" {
+		schema, err := ctx.index.AreaSchema(ctx.lastID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.index.PutAreaSchema(ctx.lastID, schema.Merge(jdex.Schema{field.Key: field}))
+	}
+
+	schema, err := ctx.index.CategorySchema(ctx.lastID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.index.PutCategorySchema(ctx.lastID, schema.Merge(jdex.Schema{field.Key: field}))
+}
+
+// Parses a schema field's attributes, such as `decimal required default=0`.
+func readSchemaField(key string, attributes string) (field jdex.Field, err error) {
+	field.Key = key
+
+	tokens, err := splitSchemaAttributes(attributes)
+	if err != nil {
+		return
+	}
+
+	var defaultText *string
+	for _, token := range tokens {
+		switch {
+		case token == "required":
+			field.Required = true
+		case token == "optional":
+			field.Required = false
+		case strings.HasPrefix(token, "default="):
+			text := strings.TrimPrefix(token, "default=")
+			defaultText = &text
+		case strings.HasPrefix(token, "values="):
+			field.Allowed = strings.Split(strings.TrimPrefix(token, "values="), "|")
+		default:
+			field.Type, err = jdex.ParseValueType(token)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	if defaultText != nil {
+		var value jdex.Value
+		value, err = field.Convert(jdex.StringValue(*defaultText))
+		if err != nil {
+			err = fmt.Errorf("default for %q: %w", key, err)
+			return
+		}
+
+		field.Default = &value
+	}
+
+	return
+}
+
+// Splits on whitespace, keeping double-quoted text together.
+func splitSchemaAttributes(attributes string) (tokens []string, err error) {
+	var token strings.Builder
+	inToken := false
+
+	for i := 0; i < len(attributes); i++ {
+		c := attributes[i]
+		switch {
+		case c == '"':
+			var quoted string
+			quoted, err = strconv.QuotedPrefix(attributes[i:])
+			if err != nil {
+				return nil, fmt.Errorf("unterminated quote in %q", attributes)
+			}
+
+			unquoted, _ := strconv.Unquote(quoted)
+			token.WriteString(unquoted)
+			inToken = true
+			i += len(quoted) - 1
+		case c == ' ' || c == '\t':
+			if inToken {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				inToken = false
+			}
+		default:
+			token.WriteByte(c)
+			inToken = true
+		}
+	}
+
+	if inToken {
+		tokens = append(tokens, token.String())
+	}
+
+	return
+}
+
+// Splits a declared type, such as `(date)`, off the end of a metadata key.
+// Anything else in brackets is part of the key.
+func readSplitMetadataType(text string) (key string, typeName string) {
+	matches := metadataTypeRegex.FindStringSubmatch(text)
+	if matches == nil {
+		return text, ""
+	}
+
+	if _, err := jdex.ParseValueType(matches[2]); err != nil {
+		return text, ""
+	}
+
+	return matches[1], matches[2]
+}
+
+// Parses a metadata value, inferring its type unless one was declared.
+func readMetadataValue(typeName string, text string) (value jdex.Value, err error) {
+	if typeName == "" {
+		return jdex.InferValue(text), nil
+	}
+
+	valueType, err := jdex.ParseValueType(typeName)
+	if err != nil {
+		return
+	}
+
+	return jdex.NewValue(valueType, text)
 }
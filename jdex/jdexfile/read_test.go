@@ -0,0 +1,184 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdexfile_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdex/jdexfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func readIndex(t *testing.T, text string) *jdex.Index {
+	index, err := jdexfile.Read(strings.NewReader(text))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return index
+}
+
+func TestRead_TrailingTags(t *testing.T) {
+	index := readIndex(t, `
+10-19 Finance
+  11 Clients
+    11.01 Issue #42
+    11.02 Meeting #1.
+    11.03 Acme #Clients
+    11.04 Globex #bills #tax
+    11.05 Issue #42 #bills
+    11.06 #tax
+`)
+
+	for _, test := range []struct {
+		id   string
+		name string
+		tags []string
+	}{
+		{"11.01", "Issue #42", nil},
+		{"11.02", "Meeting #1.", nil},
+		{"11.03", "Acme #Clients", nil},
+		{"11.04", "Globex", []string{"bills", "tax"}},
+		{"11.05", "Issue #42", []string{"bills"}},
+		{"11.06", "#tax", nil},
+	} {
+		entry, err := index.Entry(jdex.MustParseACID(test.id))
+		assert.NoError(t, err)
+		assert.Equal(t, test.name, entry.Name, test.id)
+		assert.Equal(t, test.tags, entry.Tags, test.id)
+	}
+}
+
+func TestRead_TimestampKeys(t *testing.T) {
+	index := readIndex(t, `
+10-19 Finance
+  11 Clients
+    11.01 Acme
+      - Created: 2024-05-01T10:00:00Z
+      - Modified: soon
+    11.02 Globex
+      - Created: 2024-05-01
+      - Created (datetime): 2024-06-01T10:00:00Z
+`)
+
+	acme, err := index.Entry(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), acme.Created.UTC())
+	assert.True(t, acme.Modified.IsZero())
+	assert.Equal(t, map[string]jdex.Value{"Modified": jdex.StringValue("soon")}, acme.Metadata)
+
+	// a declared type marks the key as the user's
+	globex, err := index.Entry(jdex.MustParseACID("11.02"))
+	assert.NoError(t, err)
+	assert.True(t, globex.Created.IsZero())
+	assert.Equal(t, jdex.TypeDateTime, globex.Metadata["Created"].Type)
+}
+
+func TestRead_MetadataTypes(t *testing.T) {
+	index := readIndex(t, `
+10-19 Finance
+  11 Clients
+    11.01 Acme
+      - Note (old): x
+      - Due (date): 2024-05-01
+      - Amount (Decimal): 5
+      - Seen (date) (string): yesterday
+`)
+
+	entry, err := index.Entry(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.StringValue("x"), entry.Metadata["Note (old)"])
+	assert.Equal(t, jdex.TypeDate, entry.Metadata["Due"].Type)
+	assert.Equal(t, "5", entry.Metadata["Amount"].String())
+	assert.Equal(t, jdex.TypeDecimal, entry.Metadata["Amount"].Type)
+	assert.Equal(t, jdex.StringValue("yesterday"), entry.Metadata["Seen (date)"])
+}
+
+func TestRead_Links(t *testing.T) {
+	index := readIndex(t, `
+10-19 Finance
+  11 Accounts
+    11.02 Current
+  12 Contracts
+    % Billed to: acid
+    12.04 Phone
+      - Paid from (acid): 11.02
+      - Billed to: 11.02
+      - Amount: 11.02
+`)
+
+	assert.ElementsMatch(t, []jdex.Link{
+		{From: jdex.MustParseACID("12.04"), To: jdex.MustParseACID("11.02"), Key: "Paid from"},
+		{From: jdex.MustParseACID("12.04"), To: jdex.MustParseACID("11.02"), Key: "Billed to"},
+	}, index.Backlinks(jdex.MustParseACID("11.02")))
+
+	// only declared IDs are links, so amounts can't be taken for them
+	entry, _ := index.Entry(jdex.MustParseACID("12.04"))
+	amount, err := entry.Decimal("Amount")
+	assert.NoError(t, err)
+	assert.Equal(t, "11.02", amount.FloatString(2))
+}
+
+func TestRead_TagList(t *testing.T) {
+	index := readIndex(t, `
+10-19 Finance
+  11 Clients
+    11.01 Release #v2
+      - Tags: work, c sharp
+    11.02 Build #v3 #ci
+      - Tags:
+`)
+
+	release, err := index.Entry(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Release #v2", release.Name)
+	assert.Equal(t, []string{"c-sharp", "work"}, release.Tags)
+
+	build, err := index.Entry(jdex.MustParseACID("11.02"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Build #v3 #ci", build.Name)
+	assert.Empty(t, build.Tags)
+}
+
+func TestRead_Scheme(t *testing.T) {
+	header := `00-09 System
+  00 Index
+    00.00 System Index
+      - Scheme: strict
+`
+
+	for _, ids := range [][2]string{
+		{"1A", "1A.01"},
+		{"11", "11.0A"},
+		{"11", "11.001"},
+	} {
+		_, err := jdexfile.Read(strings.NewReader(header + `10-19 Finance
+  ` + ids[0] + ` Clients
+    ` + ids[1] + ` Acme
+`))
+		assert.ErrorIs(t, err, jdex.ErrIDScheme, ids[1])
+	}
+
+	index := readIndex(t, strings.Replace(header, "strict", "extended", 1)+`10-19 Finance
+  1A Clients
+    1A.0BC Acme
+`)
+	assert.Equal(t, jdex.ExtendedScheme, index.Scheme())
+}
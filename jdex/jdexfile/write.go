@@ -19,48 +19,180 @@ package jdexfile
 import (
 	"fmt"
 	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/itisrazza/rzjd/jdex"
 )
 
 func Write(index *jdex.Index, w io.Writer) (err error) {
-	for _, areaID := range index.AreaIndexes() {
-		areaName, _ := index.AreaName(areaID)
+	for id, level := range index.Walk(jdex.ACID{}) {
+		switch level {
+		case jdex.LevelArea:
+			err = writeAreaLines(index, w, id)
+		case jdex.LevelCategory:
+			err = writeCategoryLines(index, w, id)
+		case jdex.LevelEntry:
+			entry, _ := index.Entry(id)
+			err = writeEntryLines(w, "    ", entry)
+		case jdex.LevelSub:
+			entry, _ := index.Entry(id)
+			err = writeEntryLines(w, "      ", entry)
+		}
 
-		_, err = fmt.Fprintf(w, "%s %s\n", areaID.AreaString(), areaName)
 		if err != nil {
 			return
 		}
+	}
+
+	return nil
+}
+
+// Writes an area's line followed by its metadata and schema.
+func writeAreaLines(index *jdex.Index, w io.Writer, areaID jdex.ACID) (err error) {
+	areaName, _ := index.AreaName(areaID)
+
+	_, err = fmt.Fprintf(w, "%s %s\n", areaID.AreaString(), areaName)
+	if err != nil {
+		return
+	}
+
+	areaMetadata, _ := index.AreaMetadata(areaID)
+	err = writeMetadataLines(w, "  ", areaMetadata)
+	if err != nil {
+		return
+	}
+
+	areaSchema, _ := index.AreaSchema(areaID)
+	return writeSchemaLines(w, "  ", areaSchema)
+}
+
+// Writes a category's line followed by its metadata and schema.
+func writeCategoryLines(index *jdex.Index, w io.Writer, categoryID jdex.ACID) (err error) {
+	categoryName, _ := index.CategoryName(categoryID)
+
+	_, err = fmt.Fprintf(w, "  %s %s\n", categoryID.CategoryString(), categoryName)
+	if err != nil {
+		return
+	}
+
+	categoryMetadata, _ := index.CategoryMetadata(categoryID)
+	err = writeMetadataLines(w, "    ", categoryMetadata)
+	if err != nil {
+		return
+	}
 
-		categoryIndexes, _ := index.Categories(areaID)
-		for _, categoryID := range categoryIndexes {
-			categoryName, _ := index.CategoryName(categoryID)
-
-			_, err = fmt.Fprintf(w, "  %s %s\n", categoryID.CategoryString(), categoryName)
-			if err != nil {
-				return
-			}
-
-			entries, _ := index.Entries(categoryID)
-			for _, entryID := range entries {
-				entry, _ := index.Entry(entryID)
-
-				_, err = fmt.Fprintf(w, "    %s %s\n", entry.ID.String(), entry.Name)
-				if err != nil {
-					return
-				}
-
-				if entry.Metadata != nil {
-					for key, value := range entry.Metadata {
-						_, err = fmt.Fprintf(w, "      - %s: %s", key, value)
-						if err != nil {
-							return
-						}
-					}
-				}
-			}
+	categorySchema, _ := index.CategorySchema(categoryID)
+	return writeSchemaLines(w, "    ", categorySchema)
+}
+
+// Writes an entry's line followed by its timestamps and metadata.
+func writeEntryLines(w io.Writer, indent string, entry jdex.Entry) (err error) {
+	// a name ending in what reads as a tag has its tags written as a list,
+	// which keeps the whole line as its name
+	_, nameTags := readSplitTags(entry.Name)
+	tagList := len(nameTags) > 0
+
+	line := fmt.Sprintf("%s%s %s", indent, entry.ID.String(), entry.Name)
+	if !tagList {
+		for _, tag := range entry.Tags {
+			line += " #" + tag
 		}
 	}
 
-	return nil
+	_, err = fmt.Fprintln(w, line)
+	if err != nil {
+		return
+	}
+
+	if tagList {
+		_, err = fmt.Fprintln(w, strings.TrimRight(indent+"  - Tags: "+strings.Join(entry.Tags, ", "), " "))
+		if err != nil {
+			return
+		}
+	}
+
+	err = writeTimestampLines(w, indent+"  ", entry)
+	if err != nil {
+		return
+	}
+
+	return writeMetadataLines(w, indent+"  ", entry.Metadata)
+}
+
+// Writes an entry's timestamps as `Created` and `Modified` metadata lines.
+func writeTimestampLines(w io.Writer, indent string, entry jdex.Entry) (err error) {
+	if !entry.Created.IsZero() {
+		_, err = fmt.Fprintf(w, "%s- Created: %s\n", indent, entry.Created.Format(time.RFC3339))
+		if err != nil {
+			return
+		}
+	}
+
+	if !entry.Modified.IsZero() {
+		_, err = fmt.Fprintf(w, "%s- Modified: %s\n", indent, entry.Modified.Format(time.RFC3339))
+	}
+
+	return
+}
+
+// Writes one metadata line per key, declaring the type only when reading the
+// value back wouldn't infer it, might take it for a timestamp, or would take
+// the end of the key for a type.
+func writeMetadataLines(w io.Writer, indent string, metadata map[string]jdex.Value) (err error) {
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		value := metadata[key]
+		text := value.String()
+
+		_, keyType := readSplitMetadataType(key)
+		declare := jdex.InferValue(text).Type != value.Type || isTimestampKey(key) || keyType != ""
+
+		if !declare {
+			_, err = fmt.Fprintf(w, "%s- %s: %s\n", indent, key, text)
+		} else {
+			_, err = fmt.Fprintf(w, "%s- %s (%s): %s\n", indent, key, value.Type, text)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Writes a schema as one `% Key: attributes` line per field.
+func writeSchemaLines(w io.Writer, indent string, schema jdex.Schema) (err error) {
+	for _, key := range schema.Keys() {
+		field := schema[key]
+
+		attributes := []string{field.Type.String()}
+		if field.Required {
+			attributes = append(attributes, "required")
+		}
+		if len(field.Allowed) > 0 {
+			attributes = append(attributes, "values="+quoteSchemaAttribute(strings.Join(field.Allowed, "|")))
+		}
+		if field.Default != nil {
+			attributes = append(attributes, "default="+quoteSchemaAttribute(field.Default.String()))
+		}
+
+		_, err = fmt.Fprintf(w, "%s%% %s: %s\n", indent, key, strings.Join(attributes, " "))
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func quoteSchemaAttribute(text string) string {
+	if text == "" || strings.ContainsAny(text, " \t\"") {
+		return strconv.Quote(text)
+	}
+
+	return text
 }
@@ -0,0 +1,142 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdexfile_test
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdex/jdexfile"
+	"github.com/stretchr/testify/assert"
+)
+
+// Written as Write writes it, so reading and writing it back changes nothing.
+const roundTripIndex = `00-09 System
+  00 Index
+    00.00 System Index
+      - Format: jdex
+      - Scheme: strict
+10-19 Finance
+  - Owner: Razza
+  % Amount: decimal required
+  11 Clients
+    - Active: true
+    % Status: string values="open|paid|on hold" default=open
+    11.01 Issue #42 #bills #tax
+      - Created: 2024-05-01T10:00:00Z
+      - Modified: 2024-06-01T12:30:00Z
+      - Amount: 120.50
+      - Count: 3
+      - Created (date): 2024-04-01
+      - Due: 2024-07-01
+      - Folder: /home/razza/acme
+      - Note (old): kept
+      - Paid from (acid): 11.02
+      - Price: 12.50
+      - Reference (string): 42
+      - Sent: 2024-06-01T09:00:00Z
+      - Status: open
+      11.01+001 Invoice
+        - Amount: 5
+    11.02 Current
+      - Amount (decimal): 0
+      - Status: paid
+    11.03 Release #v2
+      - Tags: work
+    11.04 Build #ci
+      - Tags:
+`
+
+func TestWrite_RoundTrip(t *testing.T) {
+	index := readIndex(t, roundTripIndex)
+
+	var buffer bytes.Buffer
+	assert.NoError(t, jdexfile.Write(index, &buffer))
+	assert.Equal(t, roundTripIndex, buffer.String())
+
+	entry, err := index.Entry(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Issue #42", entry.Name)
+	assert.Equal(t, []string{"bills", "tax"}, entry.Tags)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), entry.Created.UTC())
+
+	for key, valueType := range map[string]jdex.ValueType{
+		"Amount":     jdex.TypeDecimal,
+		"Count":      jdex.TypeInteger,
+		"Created":    jdex.TypeDate,
+		"Due":        jdex.TypeDate,
+		"Folder":     jdex.TypePath,
+		"Note (old)": jdex.TypeString,
+		"Paid from":  jdex.TypeACID,
+		"Price":      jdex.TypeDecimal,
+		"Reference":  jdex.TypeString,
+		"Sent":       jdex.TypeDateTime,
+		"Status":     jdex.TypeString,
+	} {
+		assert.Equal(t, valueType, entry.Metadata[key].Type, key)
+	}
+
+	schema, err := index.Schema(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"open", "paid", "on hold"}, schema["Status"].Allowed)
+	assert.True(t, schema["Amount"].Required)
+}
+
+func TestWrite_RoundTrip_Built(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	id := jdex.MustParseACID("11.01")
+	index.PutArea(id, "Finance")
+	index.PutCategory(id, "Clients")
+
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	written := jdex.Entry{
+		ID:       id,
+		Name:     "Release #v2",
+		Tags:     []string{"c-sharp", "work/clients"},
+		Created:  created,
+		Modified: created.Add(time.Hour),
+		Metadata: map[string]jdex.Value{
+			"Amount":    jdex.DecimalValue(big.NewRat(1250, 100), 2),
+			"Modified":  jdex.StringValue("2024-05-01T10:00:00Z"),
+			"Paid from": jdex.ACIDValue(jdex.MustParseACID("11.02")),
+			"Paid":      jdex.BoolValue(false),
+			"Due (old)": jdex.DateValue(created),
+			"Code":      jdex.StringValue("007"),
+			"Count":     jdex.StringValue("12"),
+		},
+	}
+	assert.NoError(t, index.LoadEntry(written))
+
+	var buffer bytes.Buffer
+	assert.NoError(t, jdexfile.Write(index, &buffer))
+
+	read, err := jdexfile.Read(strings.NewReader(buffer.String()))
+	if !assert.NoError(t, err, buffer.String()) {
+		t.FailNow()
+	}
+
+	entry, err := read.Entry(id)
+	assert.NoError(t, err)
+	assert.Equal(t, written.Tags, entry.Tags)
+	assert.Equal(t, written.Metadata, entry.Metadata)
+	assert.True(t, written.Created.Equal(entry.Created))
+	assert.True(t, written.Modified.Equal(entry.Modified))
+}
@@ -0,0 +1,234 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdexgraph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// Graph formats which can be written.
+const (
+	FormatDot     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// Depths the graph can be limited to.
+const (
+	DepthAreas      = 1
+	DepthCategories = 2
+	DepthEntries    = 3
+)
+
+// Options limit what goes in the graph.
+type Options struct {
+	Area  *byte  // Only include this area.
+	Tag   string // Only include entries with this tag, and their parents.
+	Depth int    // How far down the hierarchy to go, or 0 for everything.
+}
+
+type node struct {
+	id    string
+	label string
+}
+
+type edge struct {
+	from  string
+	to    string
+	label string
+	link  bool
+}
+
+type graph struct {
+	nodes []node
+	edges []edge
+}
+
+var ErrUnknownFormat = errors.New("unknown graph format")
+
+// Write the area, category and entry hierarchy, and the links between
+// entries, as a graph.
+func Write(index *jdex.Index, w io.Writer, format string, options Options) error {
+	g := build(index, options)
+
+	switch format {
+	case FormatDot:
+		return writeDot(g, w)
+	case FormatMermaid:
+		return writeMermaid(g, w)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+func build(index *jdex.Index, options Options) (g graph) {
+	depth := options.Depth
+	if depth <= 0 {
+		depth = DepthEntries
+	}
+
+	included := map[string]bool{}
+	var entries []jdex.Entry
+
+	for areaID := range index.Children(jdex.ACID{}) {
+		if options.Area != nil && areaID.Area != *options.Area {
+			continue
+		}
+
+		var categoryNodes []node
+		var categoryEdges []edge
+
+		for categoryID := range index.Children(areaID) {
+			var entryNodes []node
+			var entryEdges []edge
+
+			for entryID := range index.Children(categoryID) {
+				entry, _ := index.Entry(entryID)
+				if options.Tag != "" && !entry.HasTag(options.Tag) {
+					continue
+				}
+
+				entryNodes = append(entryNodes, node{entry.ID.String(), entry.ID.String() + " " + entry.Name})
+				entryEdges = append(entryEdges, edge{from: categoryID.CategoryString(), to: entry.ID.String()})
+				entries = append(entries, entry)
+			}
+
+			if options.Tag != "" && len(entryNodes) == 0 {
+				continue
+			}
+
+			categoryName, _ := index.CategoryName(categoryID)
+			categoryNodes = append(categoryNodes, node{categoryID.CategoryString(), categoryID.CategoryString() + " " + categoryName})
+			categoryEdges = append(categoryEdges, edge{from: areaID.AreaString(), to: categoryID.CategoryString()})
+			if depth >= DepthEntries {
+				categoryNodes = append(categoryNodes, entryNodes...)
+				categoryEdges = append(categoryEdges, entryEdges...)
+			}
+		}
+
+		if options.Tag != "" && len(categoryNodes) == 0 {
+			continue
+		}
+
+		areaName, _ := index.AreaName(areaID)
+		g.nodes = append(g.nodes, node{areaID.AreaString(), areaID.AreaString() + " " + areaName})
+		if depth >= DepthCategories {
+			g.nodes = append(g.nodes, categoryNodes...)
+			g.edges = append(g.edges, categoryEdges...)
+		}
+	}
+
+	for _, n := range g.nodes {
+		included[n.id] = true
+	}
+
+	for _, entry := range entries {
+		for _, link := range index.Links(entry.ID) {
+			if included[link.From.String()] && included[link.To.String()] {
+				g.edges = append(g.edges, edge{
+					from:  link.From.String(),
+					to:    link.To.String(),
+					label: link.Key,
+					link:  true,
+				})
+			}
+		}
+	}
+
+	return
+}
+
+func writeDot(g graph, w io.Writer) (err error) {
+	_, err = fmt.Fprint(w, "digraph rzjd {\n  rankdir=LR;\n  node [shape=box];\n")
+	if err != nil {
+		return
+	}
+
+	for _, n := range g.nodes {
+		_, err = fmt.Fprintf(w, "  %s [label=%s];\n", dotQuote(n.id), dotQuote(n.label))
+		if err != nil {
+			return
+		}
+	}
+
+	for _, e := range g.edges {
+		attributes := ""
+		if e.link {
+			attributes = " [style=dashed"
+			if e.label != "" {
+				attributes += ", label=" + dotQuote(e.label)
+			}
+			attributes += "]"
+		}
+
+		_, err = fmt.Fprintf(w, "  %s -> %s%s;\n", dotQuote(e.from), dotQuote(e.to), attributes)
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = fmt.Fprint(w, "}\n")
+	return
+}
+
+func writeMermaid(g graph, w io.Writer) (err error) {
+	_, err = fmt.Fprint(w, "flowchart LR\n")
+	if err != nil {
+		return
+	}
+
+	for _, n := range g.nodes {
+		_, err = fmt.Fprintf(w, "  %s[\"%s\"]\n", mermaidID(n.id), mermaidEscape(n.label))
+		if err != nil {
+			return
+		}
+	}
+
+	for _, e := range g.edges {
+		arrow := "-->"
+		if e.link {
+			arrow = "-.->"
+			if e.label != "" {
+				arrow += "|" + mermaidEscape(e.label) + "|"
+			}
+		}
+
+		_, err = fmt.Fprintf(w, "  %s %s %s\n", mermaidID(e.from), arrow, mermaidID(e.to))
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func dotQuote(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+}
+
+// Mermaid node IDs can't contain dots, dashes or plus signs.
+func mermaidID(id string) string {
+	return "n" + strings.NewReplacer(".", "_", "-", "_to_", "+", "_sub_").Replace(id)
+}
+
+func mermaidEscape(text string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;").Replace(text)
+}
@@ -0,0 +1,176 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdexgraph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdex/jdexfile"
+	"github.com/itisrazza/rzjd/jdex/jdexgraph"
+	"github.com/stretchr/testify/assert"
+)
+
+const graphIndex = `10-19 Finance
+  11 Clients
+    11.01 Acme #key
+    11.02 "Globex" Corp
+      - Parent (acid): 11.01
+  12 Invoices
+    12.01 Acme invoice #key
+      - Client (acid): 11.01
+20-29 Home
+  21 House
+    21.01 Roof #key
+`
+
+func writeGraph(t *testing.T, format string, options jdexgraph.Options) string {
+	index, err := jdexfile.Read(strings.NewReader(graphIndex))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	var out strings.Builder
+	if !assert.NoError(t, jdexgraph.Write(index, &out, format, options)) {
+		t.FailNow()
+	}
+
+	return out.String()
+}
+
+func TestWrite_Dot(t *testing.T) {
+	assert.Equal(t, `digraph rzjd {
+  rankdir=LR;
+  node [shape=box];
+  "00-09" [label="00-09 System"];
+  "00" [label="00 Index"];
+  "00.00" [label="00.00 System Index"];
+  "10-19" [label="10-19 Finance"];
+  "11" [label="11 Clients"];
+  "11.01" [label="11.01 Acme"];
+  "11.02" [label="11.02 \"Globex\" Corp"];
+  "12" [label="12 Invoices"];
+  "12.01" [label="12.01 Acme invoice"];
+  "20-29" [label="20-29 Home"];
+  "21" [label="21 House"];
+  "21.01" [label="21.01 Roof"];
+  "00-09" -> "00";
+  "00" -> "00.00";
+  "10-19" -> "11";
+  "11" -> "11.01";
+  "11" -> "11.02";
+  "10-19" -> "12";
+  "12" -> "12.01";
+  "20-29" -> "21";
+  "21" -> "21.01";
+  "11.02" -> "11.01" [style=dashed, label="Parent"];
+  "12.01" -> "11.01" [style=dashed, label="Client"];
+}
+`, writeGraph(t, jdexgraph.FormatDot, jdexgraph.Options{}))
+}
+
+func TestWrite_Mermaid(t *testing.T) {
+	assert.Equal(t, `flowchart LR
+  n00_to_09["00-09 System"]
+  n00["00 Index"]
+  n00_00["00.00 System Index"]
+  n10_to_19["10-19 Finance"]
+  n11["11 Clients"]
+  n11_01["11.01 Acme"]
+  n11_02["11.02 #quot;Globex#quot; Corp"]
+  n12["12 Invoices"]
+  n12_01["12.01 Acme invoice"]
+  n20_to_29["20-29 Home"]
+  n21["21 House"]
+  n21_01["21.01 Roof"]
+  n00_to_09 --> n00
+  n00 --> n00_00
+  n10_to_19 --> n11
+  n11 --> n11_01
+  n11 --> n11_02
+  n10_to_19 --> n12
+  n12 --> n12_01
+  n20_to_29 --> n21
+  n21 --> n21_01
+  n11_02 -.->|Parent| n11_01
+  n12_01 -.->|Client| n11_01
+`, writeGraph(t, jdexgraph.FormatMermaid, jdexgraph.Options{}))
+}
+
+func TestWrite_Tag(t *testing.T) {
+	assert.Equal(t, `flowchart LR
+  n10_to_19["10-19 Finance"]
+  n11["11 Clients"]
+  n11_01["11.01 Acme"]
+  n12["12 Invoices"]
+  n12_01["12.01 Acme invoice"]
+  n20_to_29["20-29 Home"]
+  n21["21 House"]
+  n21_01["21.01 Roof"]
+  n10_to_19 --> n11
+  n11 --> n11_01
+  n10_to_19 --> n12
+  n12 --> n12_01
+  n20_to_29 --> n21
+  n21 --> n21_01
+  n12_01 -.->|Client| n11_01
+`, writeGraph(t, jdexgraph.FormatMermaid, jdexgraph.Options{Tag: "key"}))
+}
+
+func TestWrite_Depth(t *testing.T) {
+	assert.Equal(t, `digraph rzjd {
+  rankdir=LR;
+  node [shape=box];
+  "00-09" [label="00-09 System"];
+  "00" [label="00 Index"];
+  "10-19" [label="10-19 Finance"];
+  "11" [label="11 Clients"];
+  "12" [label="12 Invoices"];
+  "20-29" [label="20-29 Home"];
+  "21" [label="21 House"];
+  "00-09" -> "00";
+  "10-19" -> "11";
+  "10-19" -> "12";
+  "20-29" -> "21";
+}
+`, writeGraph(t, jdexgraph.FormatDot, jdexgraph.Options{Depth: jdexgraph.DepthCategories}))
+}
+
+func TestWrite_Area(t *testing.T) {
+	area := byte('2')
+	assert.Equal(t, `digraph rzjd {
+  rankdir=LR;
+  node [shape=box];
+  "20-29" [label="20-29 Home"];
+  "21" [label="21 House"];
+  "21.01" [label="21.01 Roof"];
+  "20-29" -> "21";
+  "21" -> "21.01";
+}
+`, writeGraph(t, jdexgraph.FormatDot, jdexgraph.Options{Area: &area}))
+
+	assert.Equal(t, `flowchart LR
+  n20_to_29["20-29 Home"]
+`, writeGraph(t, jdexgraph.FormatMermaid, jdexgraph.Options{Area: &area, Depth: jdexgraph.DepthAreas}))
+}
+
+func TestWrite_FailUnknownFormat(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	err := jdexgraph.Write(index, &strings.Builder{}, "svg", jdexgraph.Options{})
+	assert.ErrorIs(t, err, jdexgraph.ErrUnknownFormat)
+}
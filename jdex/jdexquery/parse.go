@@ -0,0 +1,531 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdexquery
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenOpen
+	tokenClose
+	tokenEnd
+)
+
+type token struct {
+	kind tokenKind
+	raw  string // Text as written, quotes and all.
+	pos  int
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	now    time.Time
+}
+
+// Tells whether a result matches part of a query.
+type predicate func(result *Result) bool
+
+// Operators, longest first so `<=` isn't taken for `<`.
+var operators = []string{"!=", "<=", ">=", "=", "<", ">", ":", "~"}
+
+var ErrSyntax = errors.New("query is not valid")
+var ErrOperator = errors.New("operator can't be used with this field")
+
+// Split input into words and brackets. Quoted text is kept whole, so it can
+// hold spaces and brackets.
+func lex(input string) (tokens []token, err error) {
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen, raw: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose, raw: ")", pos: i})
+			i++
+		default:
+			start := i
+			quoted := false
+			for ; i < len(input); i++ {
+				c := input[i]
+				if quoted && c == '\\' {
+					i++
+				} else if c == '"' {
+					quoted = !quoted
+				} else if !quoted && (unicode.IsSpace(rune(c)) || c == '(' || c == ')') {
+					break
+				}
+			}
+
+			if quoted {
+				return nil, fmt.Errorf("%w: unterminated quote at %d", ErrSyntax, start)
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, raw: input[start:i], pos: start})
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEnd, pos: len(input)})
+	return
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+
+	return t
+}
+
+// Whether the next token is the keyword, such as `or`, written unquoted.
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokenWord && strings.EqualFold(t.raw, word)
+}
+
+func (p *parser) parseOr() (predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("or") {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = or(left, right)
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (predicate, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch t := p.peek(); {
+		case t.kind == tokenEnd, t.kind == tokenClose, p.keyword("or"):
+			return left, nil
+		case p.keyword("and"):
+			p.next()
+		}
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = and(left, right)
+	}
+}
+
+func (p *parser) parseNot() (predicate, error) {
+	if p.keyword("not") {
+		p.next()
+
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return not(inner), nil
+	}
+
+	if t := p.peek(); t.kind == tokenWord && len(t.raw) > 1 && t.raw[0] == '-' {
+		p.next()
+
+		inner, err := p.parseTerm(token{kind: tokenWord, raw: t.raw[1:], pos: t.pos + 1})
+		if err != nil {
+			return nil, err
+		}
+
+		return not(inner), nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (predicate, error) {
+	switch t := p.next(); t.kind {
+	case tokenOpen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != tokenClose {
+			return nil, fmt.Errorf("%w: expected ) at %d", ErrSyntax, closing.pos)
+		}
+
+		return inner, nil
+	case tokenWord:
+		return p.parseTerm(t)
+	case tokenClose:
+		return nil, fmt.Errorf("%w: unexpected ) at %d", ErrSyntax, t.pos)
+	default:
+		return nil, fmt.Errorf("%w: expected a condition at %d", ErrSyntax, t.pos)
+	}
+}
+
+// Parse a single condition, such as `acme`, `#tax`, `name~^ac` or
+// `Status=open`.
+func (p *parser) parseTerm(t token) (predicate, error) {
+	key, keyQuoted, op, value, valueQuoted := splitTerm(t.raw)
+
+	if op == "" {
+		if !valueQuoted && strings.HasPrefix(value, "#") {
+			return tagPredicate(value)
+		}
+
+		return nameContains(value), nil
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("%w: missing field before %s at %d", ErrSyntax, op, t.pos)
+	}
+
+	if value == "" && !valueQuoted {
+		return nil, fmt.Errorf("%w: missing value after %s%s at %d", ErrSyntax, key, op, t.pos)
+	}
+
+	pred, err := p.fieldPredicate(key, keyQuoted, op, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.raw, err)
+	}
+
+	return pred, nil
+}
+
+func (p *parser) fieldPredicate(key string, keyQuoted bool, op string, value string) (predicate, error) {
+	field := strings.ToLower(key)
+	if keyQuoted {
+		field = ""
+	}
+
+	switch field {
+	case "id":
+		return equality(op, func() (predicate, error) {
+			selector, err := jdex.ParseACIDSelector(value)
+			if err != nil {
+				return nil, err
+			}
+
+			return func(result *Result) bool {
+				return selector.Match(result.Entry.ID)
+			}, nil
+		})
+	case "area":
+		return equality(op, func() (predicate, error) {
+			return areaPredicate(value)
+		})
+	case "category":
+		return equality(op, func() (predicate, error) {
+			return categoryPredicate(value)
+		})
+	case "tag":
+		return equality(op, func() (predicate, error) {
+			return tagPredicate(value)
+		})
+	case "has":
+		return equality(op, func() (predicate, error) {
+			return func(result *Result) bool {
+				_, ok := lookup(result.Metadata, value)
+				return ok
+			}, nil
+		})
+	case "name":
+		return namePredicate(op, value)
+	case "created", "modified":
+		return p.timestampPredicate(field, op, value)
+	}
+
+	return p.metadataPredicate(key, op, value)
+}
+
+// Build a predicate for fields which can only be tested for a match, where
+// `!=` is the opposite of `:` and `=`.
+func equality(op string, build func() (predicate, error)) (predicate, error) {
+	switch op {
+	case ":", "=":
+		return build()
+	case "!=":
+		pred, err := build()
+		if err != nil {
+			return nil, err
+		}
+
+		return not(pred), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrOperator, op)
+}
+
+func areaPredicate(value string) (predicate, error) {
+	var area byte
+	switch {
+	case len(value) == 1:
+		area = value[0]
+	case len(value) == 2 && value[1] == '0':
+		area = value[0]
+	default:
+		id, level, err := jdex.ParseID(value)
+		if err != nil || level != jdex.LevelArea {
+			return nil, fmt.Errorf("%w: %q is not an area, such as 10-19", jdex.ErrInvalidID, value)
+		}
+
+		area = id.Area
+	}
+
+	return func(result *Result) bool {
+		return result.Entry.ID.Area == area
+	}, nil
+}
+
+func categoryPredicate(value string) (predicate, error) {
+	id, level, err := jdex.ParseID(value)
+	if err != nil || level != jdex.LevelCategory {
+		return nil, fmt.Errorf("%w: %q is not a category, such as 11", jdex.ErrInvalidID, value)
+	}
+
+	return func(result *Result) bool {
+		return result.Entry.ID.Area == id.Area && result.Entry.ID.Category == id.Category
+	}, nil
+}
+
+func tagPredicate(value string) (predicate, error) {
+	tag, err := jdex.NormaliseTag(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(result *Result) bool {
+		return result.Entry.HasTag(tag)
+	}, nil
+}
+
+func nameContains(value string) predicate {
+	value = strings.ToLower(value)
+	return func(result *Result) bool {
+		return strings.Contains(strings.ToLower(result.Entry.Name), value)
+	}
+}
+
+func namePredicate(op string, value string) (predicate, error) {
+	switch op {
+	case ":":
+		return nameContains(value), nil
+	case "=", "!=":
+		return equality(op, func() (predicate, error) {
+			return func(result *Result) bool {
+				return strings.EqualFold(result.Entry.Name, value)
+			}, nil
+		})
+	case "~":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(result *Result) bool {
+			return re.MatchString(result.Entry.Name)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrOperator, op)
+}
+
+func (p *parser) timestampPredicate(field string, op string, value string) (predicate, error) {
+	if op == "~" {
+		return nil, fmt.Errorf("%w: %s", ErrOperator, op)
+	}
+
+	period, err := parsePeriod(value, p.now, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(result *Result) bool {
+		timestamp := result.Entry.Modified
+		if field == "created" {
+			timestamp = result.Entry.Created
+		}
+
+		return !timestamp.IsZero() && period.compare(op, timestamp)
+	}, nil
+}
+
+// Compare a metadata value, numerically for numbers and by period for dates.
+// Entries without the key never match.
+func (p *parser) metadataPredicate(key string, op string, value string) (predicate, error) {
+	if op == "~" {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(result *Result) bool {
+			actual, ok := lookup(result.Metadata, key)
+			return ok && re.MatchString(actual.String())
+		}, nil
+	}
+
+	expected := jdex.InferValue(value)
+	_, periodErr := parsePeriod(value, p.now, time.Local)
+
+	return func(result *Result) bool {
+		actual, ok := lookup(result.Metadata, key)
+		if !ok {
+			return false
+		}
+
+		if timestamp, err := actual.Time(); err == nil && periodErr == nil {
+			location := time.Local
+			if actual.Type == jdex.TypeDate {
+				location = time.UTC
+			}
+
+			period, _ := parsePeriod(value, p.now, location)
+			return period.compare(op, timestamp)
+		}
+
+		return compare(op, jdex.CompareValues(actual, expected))
+	}, nil
+}
+
+// Get a metadata value, preferring a key with the same case.
+func lookup(metadata map[string]jdex.Value, key string) (jdex.Value, bool) {
+	if value, ok := metadata[key]; ok {
+		return value, true
+	}
+
+	for candidate, value := range metadata {
+		if strings.EqualFold(candidate, key) {
+			return value, true
+		}
+	}
+
+	return jdex.Value{}, false
+}
+
+// Check the result of a comparison against an operator.
+func compare(op string, c int) bool {
+	switch op {
+	case ":", "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+
+	return false
+}
+
+// Split a condition into its field, operator and value, such as `Status`, `=`
+// and `open`. Conditions without an operator only have a value.
+func splitTerm(raw string) (key string, keyQuoted bool, op string, value string, valueQuoted bool) {
+	quoted := false
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case !quoted:
+			for _, candidate := range operators {
+				if strings.HasPrefix(raw[i:], candidate) {
+					key, keyQuoted = unquote(raw[:i])
+					value, valueQuoted = unquote(raw[i+len(candidate):])
+					return key, keyQuoted, candidate, value, valueQuoted
+				}
+			}
+		}
+	}
+
+	value, valueQuoted = unquote(raw)
+	return
+}
+
+// Remove the quotes from text, along with the backslashes escaping quotes
+// and backslashes within them.
+func unquote(raw string) (text string, quoted bool) {
+	var b strings.Builder
+	inQuote := false
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; {
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote && c == '\\' && i+1 < len(raw):
+			i++
+			b.WriteByte(raw[i])
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), strings.Contains(raw, `"`)
+}
+
+func and(left, right predicate) predicate {
+	return func(result *Result) bool {
+		return left(result) && right(result)
+	}
+}
+
+func or(left, right predicate) predicate {
+	return func(result *Result) bool {
+		return left(result) || right(result)
+	}
+}
+
+func not(inner predicate) predicate {
+	return func(result *Result) bool {
+		return !inner(result)
+	}
+}
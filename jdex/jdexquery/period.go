@@ -0,0 +1,110 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdexquery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// A span of time, from start up to but not including end.
+type period struct {
+	start time.Time
+	end   time.Time
+}
+
+var ErrPeriod = errors.New("value is not a date or period")
+
+// Parse a date or period, such as `2025`, `2025-06`, `2025-06-30`, an RFC 3339
+// time, or one of `today`, `yesterday`, `this-week`, `last-week`,
+// `this-month`, `last-month`, `this-year` or `last-year`, relative to now.
+// Calendar days start at midnight in location.
+func parsePeriod(text string, now time.Time, location *time.Location) (p period, err error) {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, location)
+	week := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	thisMonth := time.Date(year, month, 1, 0, 0, 0, 0, location)
+	thisYear := time.Date(year, time.January, 1, 0, 0, 0, 0, location)
+
+	switch strings.ToLower(text) {
+	case "today":
+		return period{today, today.AddDate(0, 0, 1)}, nil
+	case "yesterday":
+		return period{today.AddDate(0, 0, -1), today}, nil
+	case "this-week":
+		return period{week, week.AddDate(0, 0, 7)}, nil
+	case "last-week":
+		return period{week.AddDate(0, 0, -7), week}, nil
+	case "this-month":
+		return period{thisMonth, thisMonth.AddDate(0, 1, 0)}, nil
+	case "last-month":
+		return period{thisMonth.AddDate(0, -1, 0), thisMonth}, nil
+	case "this-year":
+		return period{thisYear, thisYear.AddDate(1, 0, 0)}, nil
+	case "last-year":
+		return period{thisYear.AddDate(-1, 0, 0), thisYear}, nil
+	}
+
+	for _, layout := range []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006", 1, 0, 0},
+		{"2006-01", 0, 1, 0},
+		{jdex.DateLayout, 0, 0, 1},
+	} {
+		start, err := time.ParseInLocation(layout.layout, text, location)
+		if err == nil {
+			return period{start, start.AddDate(layout.years, layout.months, layout.days)}, nil
+		}
+	}
+
+	instant, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return period{}, fmt.Errorf("%w: %q", ErrPeriod, text)
+	}
+
+	return period{instant, instant.Add(time.Nanosecond)}, nil
+}
+
+// Check where a time falls against the period. Being equal means being
+// within it, and being before or after means being before its start or after
+// its end.
+func (p period) compare(op string, t time.Time) bool {
+	switch op {
+	case ":", "=":
+		return !t.Before(p.start) && t.Before(p.end)
+	case "!=":
+		return t.Before(p.start) || !t.Before(p.end)
+	case "<":
+		return t.Before(p.start)
+	case "<=":
+		return t.Before(p.end)
+	case ">":
+		return !t.Before(p.end)
+	case ">=":
+		return !t.Before(p.start)
+	}
+
+	return false
+}
@@ -0,0 +1,231 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdexquery
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// A parsed query, which picks out entries from an index.
+type Query struct {
+	source string
+	match  predicate
+}
+
+// An entry matched by a query.
+type Result struct {
+	Entry    jdex.Entry
+	Metadata map[string]jdex.Value // Entry's metadata, including what it inherits.
+}
+
+// A field to sort results by.
+type SortKey struct {
+	Field      string // `id`, `name`, `created`, `modified` or a metadata key.
+	Descending bool
+}
+
+// Options for running a query.
+type Options struct {
+	Sort  []SortKey // Fields to sort by, in order, before falling back to ID.
+	Limit int       // Most results to return, or 0 for all of them.
+}
+
+var ErrSortKey = errors.New("sort key is empty")
+
+/*
+Parse a query. Queries are made of conditions, all of which must match unless
+joined with `or`. Conditions can be grouped with brackets and negated with
+`not` or a leading `-`.
+
+	acme              name contains "acme", ignoring case
+	"acme corp"       quotes keep spaces and brackets together
+	#tax, tag:tax     entry has the tag
+	id:11.01-11.20    entry matches an ID, range or pattern
+	area:10-19        entry is in the area, also written area:10
+	category:11       entry is in the category
+	name:acme         name contains, name=Acme is the whole name, name~^ac
+	                  matches a regular expression
+	has:Status        entry has the metadata key
+	Status=open       metadata comparison with =, !=, <, <=, >, >= or ~
+	modified>=2025    when the entry was modified or created, compared to a
+	created:this-year date or period
+
+Numbers compare numerically. Dates compare against periods, such as `2025`,
+`2025-06`, `2025-06-30`, `today`, `this-week`, `last-month` or `this-year`,
+so `=` means within the period and `<` means before it starts. Quote a key,
+such as `"name"=x`, to compare metadata which shares its name with a field.
+*/
+func Parse(input string) (*Query, error) {
+	query := &Query{source: input, match: func(*Result) bool { return true }}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 1 {
+		return query, nil
+	}
+
+	p := parser{tokens: tokens, now: time.Now()}
+	query.match, err = p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, fmt.Errorf("%w: unexpected %s at %d", ErrSyntax, t.raw, t.pos)
+	}
+
+	return query, nil
+}
+
+func MustParse(input string) *Query {
+	query, err := Parse(input)
+	if err != nil {
+		panic(err)
+	}
+
+	return query
+}
+
+// Returns the query as it was written.
+func (query *Query) String() string {
+	return query.source
+}
+
+// Checks whether an entry in the index matches the query.
+func (query *Query) Match(index *jdex.Index, id jdex.ACID) bool {
+	result, err := newResult(index, id)
+	return err == nil && query.match(&result)
+}
+
+// Get the entries in the index matching the query, in ID order.
+func (query *Query) Find(index *jdex.Index) (results []Result) {
+	for id := range index.AllEntries() {
+		result, err := newResult(index, id)
+		if err == nil && query.match(&result) {
+			results = append(results, result)
+		}
+	}
+
+	return
+}
+
+// Get the entries in the index matching the query, sorted and limited.
+func (query *Query) Run(index *jdex.Index, options Options) []Result {
+	results := query.Find(index)
+	SortResults(results, options.Sort)
+	return Limit(results, options.Limit)
+}
+
+func newResult(index *jdex.Index, id jdex.ACID) (result Result, err error) {
+	result.Entry, err = index.Entry(id)
+	if err != nil {
+		return
+	}
+
+	result.Metadata, err = index.EffectiveMetadata(id)
+	return
+}
+
+// Parse a comma-separated list of fields to sort by, such as
+// `-modified,name`. Fields starting with `-` sort in descending order.
+func ParseSort(spec string) (keys []SortKey, err error) {
+	if spec == "" {
+		return
+	}
+
+	for _, field := range strings.Split(spec, ",") {
+		key := SortKey{Field: strings.TrimSpace(field)}
+		if rest, ok := strings.CutPrefix(key.Field, "-"); ok {
+			key.Field, key.Descending = rest, true
+		}
+
+		if key.Field == "" {
+			return nil, fmt.Errorf("%w: %q", ErrSortKey, spec)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return
+}
+
+// Sort results by the keys in turn, then by ID. Results without a metadata
+// key being sorted by go last, whichever way it's sorted.
+func SortResults(results []Result, keys []SortKey) {
+	slices.SortStableFunc(results, func(a, b Result) int {
+		for _, key := range keys {
+			c, missing := compareField(key.Field, &a, &b)
+			if key.Descending && !missing {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return a.Entry.ID.Compare(b.Entry.ID)
+	})
+}
+
+// Keep at most limit results, or all of them if limit is 0.
+func Limit(results []Result, limit int) []Result {
+	if limit > 0 && len(results) > limit {
+		return results[:limit]
+	}
+
+	return results
+}
+
+func compareField(field string, a, b *Result) (c int, missing bool) {
+	switch strings.ToLower(field) {
+	case "id":
+		return a.Entry.ID.Compare(b.Entry.ID), false
+	case "name":
+		return cmp.Compare(strings.ToLower(a.Entry.Name), strings.ToLower(b.Entry.Name)), false
+	case "created":
+		return a.Entry.Created.Compare(b.Entry.Created), false
+	case "modified":
+		return a.Entry.Modified.Compare(b.Entry.Modified), false
+	}
+
+	x, xok := lookup(a.Metadata, field)
+	y, yok := lookup(b.Metadata, field)
+	if !xok || !yok {
+		return cmp.Compare(boolRank(!xok), boolRank(!yok)), true
+	}
+
+	return jdex.CompareValues(x, y), false
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,187 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdexquery_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdex/jdexquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func newQueryIndex(t *testing.T) *jdex.Index {
+	index, _ := jdex.NewIndex()
+	index.PutArea(jdex.ACID{Area: '1'}, "Finance")
+	index.PutCategory(jdex.ACID{Area: '1', Category: "1"}, "Invoices")
+	index.PutCategory(jdex.ACID{Area: '1', Category: "2"}, "Receipts")
+	index.PutArea(jdex.ACID{Area: '2'}, "Home")
+	index.PutCategory(jdex.ACID{Area: '2', Category: "1"}, "House")
+	index.PutCategoryMetadata(jdex.ACID{Area: '1', Category: "1"}, map[string]jdex.Value{
+		"Currency": jdex.StringValue("NZD"),
+	})
+
+	modified := func(date string) time.Time {
+		t, _ := time.ParseInLocation(jdex.DateLayout, date, time.Local)
+		return t
+	}
+
+	for _, entry := range []jdex.Entry{
+		{ID: jdex.MustParseACID("11.01"), Name: "Acme Corp", Tags: []string{"tax"}, Modified: modified("2025-03-01"), Metadata: map[string]jdex.Value{
+			"Status": jdex.StringValue("open"),
+			"Amount": jdex.InferValue("120.50"),
+			"Due":    jdex.InferValue("2025-04-01"),
+		}},
+		{ID: jdex.MustParseACID("11.02"), Name: "Acme Holdings", Modified: modified("2024-11-20"), Metadata: map[string]jdex.Value{
+			"Status": jdex.StringValue("paid"),
+			"Amount": jdex.InferValue("9"),
+		}},
+		{ID: jdex.MustParseACID("11.03"), Name: "Globex (2024)", Tags: []string{"tax"}, Modified: modified("2025-06-15"), Metadata: map[string]jdex.Value{
+			"Status": jdex.StringValue("open"),
+			"Due":    jdex.InferValue("2025-07-01"),
+		}},
+		{ID: jdex.MustParseACID("12.01"), Name: "Groceries", Tags: []string{"tax"}, Modified: modified("2025-01-10")},
+		{ID: jdex.MustParseACID("21.01"), Name: "Mortgage", Modified: modified("2025-02-02")},
+	} {
+		err := index.LoadEntry(entry)
+		if !assert.NoError(t, err, entry.ID.String()) {
+			t.FailNow()
+		}
+	}
+
+	return index
+}
+
+func findIDs(t *testing.T, index *jdex.Index, input string) (ids []string) {
+	query, err := jdexquery.Parse(input)
+	if !assert.NoError(t, err, input) {
+		return
+	}
+
+	for _, result := range query.Find(index) {
+		ids = append(ids, result.Entry.ID.String())
+	}
+
+	return
+}
+
+func Test_Query_Find(t *testing.T) {
+	index := newQueryIndex(t)
+
+	for input, expected := range map[string][]string{
+		"acme":                         {"11.01", "11.02"},
+		`"acme corp"`:                  {"11.01"},
+		"#tax":                         {"11.01", "11.03", "12.01"},
+		"tag:TAX -acme":                {"11.03", "12.01"},
+		"id:11.01-11.02":               {"11.01", "11.02"},
+		"area:10 not tag:tax":          {"11.02"},
+		"area:20-29":                   {"21.01"},
+		"category:12":                  {"12.01"},
+		"name~^G":                      {"11.03", "12.01"},
+		`name:"(2024)"`:                {"11.03"},
+		"name=groceries":               {"12.01"},
+		"Status=open":                  {"11.01", "11.03"},
+		"status!=open":                 {"11.02"},
+		"Amount>100":                   {"11.01"},
+		"Amount<=9":                    {"11.02"},
+		"Amount>12.50":                 {"11.01"},
+		"has:Due":                      {"11.01", "11.03"},
+		"Currency=NZD":                 {"11.01", "11.02", "11.03"},
+		"Due<2025-06":                  {"11.01"},
+		"Due=2025-07":                  {"11.03"},
+		"modified=2025":                {"11.01", "11.03", "12.01", "21.01"},
+		"modified>=2025-03":            {"11.01", "11.03"},
+		"modified<2025-01-10":          {"11.02"},
+		"#tax and (acme or Groceries)": {"11.01", "12.01"},
+		"Status=open or area:2":        {"11.01", "11.03", "21.01"},
+	} {
+		assert.Equal(t, expected, findIDs(t, index, input), input)
+	}
+}
+
+func Test_Query_Relative(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	index.PutArea(jdex.ACID{Area: '1'}, "")
+	index.PutCategory(jdex.ACID{Area: '1', Category: "1"}, "")
+	index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.01"), Name: "Now"})
+	index.LoadEntry(jdex.Entry{ID: jdex.MustParseACID("11.02"), Name: "Old", Modified: time.Now().AddDate(-2, 0, 0)})
+
+	assert.Equal(t, []string{"11.01"}, findIDs(t, index, "id:11.* modified:today"))
+	assert.Equal(t, []string{"11.01"}, findIDs(t, index, "id:11.* modified:this-year"))
+	assert.Equal(t, []string{"11.02"}, findIDs(t, index, "id:11.* modified<last-year"))
+}
+
+func Test_Query_Match(t *testing.T) {
+	index := newQueryIndex(t)
+	query := jdexquery.MustParse("#tax Status=open")
+
+	assert.True(t, query.Match(index, jdex.MustParseACID("11.01")))
+	assert.False(t, query.Match(index, jdex.MustParseACID("12.01")))
+	assert.False(t, query.Match(index, jdex.MustParseACID("19.99")))
+}
+
+func Test_Query_Parse_Errors(t *testing.T) {
+	for _, input := range []string{
+		"(acme",
+		"acme)",
+		`"acme`,
+		"=open",
+		"Status=",
+		"tag<tax",
+		"modified~2025",
+		"modified>soon",
+		"area:11.01",
+		"name~(",
+	} {
+		_, err := jdexquery.Parse(input)
+		assert.Error(t, err, input)
+	}
+
+	_, err := jdexquery.Parse("acme)")
+	assert.ErrorIs(t, err, jdexquery.ErrSyntax)
+
+	_, err = jdexquery.Parse("tag<tax")
+	assert.ErrorIs(t, err, jdexquery.ErrOperator)
+
+	_, err = jdexquery.Parse("modified>soon")
+	assert.ErrorIs(t, err, jdexquery.ErrPeriod)
+}
+
+func Test_Query_Run(t *testing.T) {
+	index := newQueryIndex(t)
+	keys, err := jdexquery.ParseSort("-Amount,name")
+	assert.NoError(t, err)
+	assert.Equal(t, []jdexquery.SortKey{{Field: "Amount", Descending: true}, {Field: "name"}}, keys)
+
+	var ids []string
+	for _, result := range jdexquery.MustParse("area:1").Run(index, jdexquery.Options{Sort: keys, Limit: 3}) {
+		ids = append(ids, result.Entry.ID.String())
+	}
+
+	assert.Equal(t, []string{"11.01", "11.02", "11.03"}, ids)
+
+	ids = nil
+	for _, result := range jdexquery.MustParse("id:1*").Run(index, jdexquery.Options{Sort: []jdexquery.SortKey{{Field: "modified", Descending: true}}}) {
+		ids = append(ids, result.Entry.ID.String())
+	}
+
+	assert.Equal(t, []string{"11.03", "11.01", "12.01", "11.02"}, ids)
+
+	_, err = jdexquery.ParseSort("name,,id")
+	assert.ErrorIs(t, err, jdexquery.ErrSortKey)
+}
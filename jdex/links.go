@@ -0,0 +1,299 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+)
+
+// A reference from one entry to another.
+type Link struct {
+	From ACID
+	To   ACID
+	Key  string // Metadata key holding the link, or empty if it's in the notes.
+}
+
+var wikiLinkRegex = regexp.MustCompile(`\[\[([^\]\s|]+)[^\]]*\]\]`)
+
+var ErrEntryExists = errors.New("entry already exists")
+
+// Find the `[[AC.ID]]` links in an entry's notes.
+func FindWikiLinks(text string) (ids []ACID) {
+	for _, matches := range wikiLinkRegex.FindAllStringSubmatch(text, -1) {
+		id, err := ParseACID(matches[1])
+		if err != nil || slices.Contains(ids, id) {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	return
+}
+
+// Set the entries linked to from an entry's notes.
+func (index *Index) PutNoteLinks(id ACID, ids []ACID) error {
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().putNoteLinks(id, ids)
+}
+
+func (data *indexData) putNoteLinks(id ACID, ids []ACID) error {
+	if _, err := data.entry(id); err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		delete(data.notes, id.String())
+	} else {
+		data.notes[id.String()] = slices.Clone(ids)
+	}
+
+	return nil
+}
+
+// Get the links from an entry, through its metadata and its notes.
+func (index *Index) Links(id ACID) (links []Link) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.links(id)
+}
+
+func (data *indexData) links(id ACID) (links []Link) {
+	entry, ok := data.entries[id.String()]
+	if !ok {
+		return
+	}
+
+	// values are IDs when they're declared as IDs, or their field says so
+	schema, _ := data.schema(id)
+	for _, key := range slices.Sorted(maps.Keys(entry.Metadata)) {
+		value := entry.Metadata[key]
+		if field, ok := schema[key]; ok && field.Type == TypeACID {
+			if converted, err := field.Convert(value); err == nil {
+				value = converted
+			}
+		}
+
+		to, err := value.ACID()
+		if err != nil || to.System != "" {
+			continue
+		}
+
+		links = append(links, Link{From: id, To: to, Key: key})
+	}
+
+	for _, to := range data.notes[id.String()] {
+		links = append(links, Link{From: id, To: to})
+	}
+
+	return
+}
+
+// Get the links pointing at an entry from other entries.
+func (index *Index) Backlinks(id ACID) (links []Link) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.backlinks(id)
+}
+
+func (data *indexData) backlinks(id ACID) (links []Link) {
+	for from := range data.entriesIn(nil) {
+		if from == id {
+			continue
+		}
+
+		for _, link := range data.links(from) {
+			if link.To == id {
+				links = append(links, link)
+			}
+		}
+	}
+
+	return
+}
+
+// Get the links pointing at entries which don't exist.
+func (index *Index) DanglingLinks() (links []Link) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.danglingLinks()
+}
+
+func (data *indexData) danglingLinks() (links []Link) {
+	for from := range data.entriesIn(nil) {
+		for _, link := range data.links(from) {
+			if _, ok := data.entries[link.To.String()]; !ok {
+				links = append(links, link)
+			}
+		}
+	}
+
+	return
+}
+
+// Remove an entry along with its sub-entries, returning the links which now
+// point nowhere. The system's entries can't be removed.
+func (index *Index) RemoveEntry(id ACID) (dangling []Link, err error) {
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().removeEntry(id)
+}
+
+func (data *indexData) removeEntry(id ACID) (dangling []Link, err error) {
+	if IsProtectedACID(id) {
+		err = fmt.Errorf("%w: %q", ErrProtectedID, id.String())
+		return
+	}
+
+	if _, err = data.entry(id); err != nil {
+		return
+	}
+
+	removed := append([]ACID{id}, data.children(id)...)
+
+	// while the entry is still there for its sub-entries' events
+	for _, removedID := range removed {
+		data.emit(EventRemoved, removedID, data.entries[removedID.String()].Name)
+	}
+
+	for _, removedID := range removed {
+		entry := data.entries[removedID.String()]
+
+		if removedID.Sub != "" {
+			parentID := removedID.EntryID()
+			delete(data.subs[parentID.String()], removedID.String())
+		} else {
+			delete(data.areas[removedID.Area].categories[removedID.Category].entries, removedID.String())
+		}
+
+		delete(data.subs, removedID.String())
+		delete(data.entries, removedID.String())
+		delete(data.notes, removedID.String())
+		data.untagEntry(entry)
+	}
+
+	for _, removedID := range removed {
+		dangling = append(dangling, data.backlinks(removedID)...)
+	}
+
+	return
+}
+
+// Give an entry a new ID, returning the links which still point at the old
+// one. Its sub-entries move along with it. The system's entries can't be
+// renumbered, nor can anything take their IDs.
+func (index *Index) RenumberEntry(from ACID, to ACID) (dangling []Link, err error) {
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().renumberEntry(from, to)
+}
+
+func (data *indexData) renumberEntry(from ACID, to ACID) (dangling []Link, err error) {
+	for _, id := range []ACID{from, to} {
+		if IsProtectedACID(id) {
+			err = fmt.Errorf("%w: %q", ErrProtectedID, id.String())
+			return
+		}
+	}
+
+	entry, err := data.entry(from)
+	if err != nil {
+		return
+	}
+
+	if _, err = data.entry(to); err == nil {
+		err = ErrEntryExists
+		return
+	} else if !errors.Is(err, ErrEntryNotFound) {
+		return
+	}
+
+	if to.Sub != "" && to.EntryID() == from {
+		err = fmt.Errorf("%w: %q can't become a sub-entry of itself", ErrInvalidID, from.String())
+		return
+	}
+
+	subs := data.children(from)
+	if len(subs) > 0 && to.Sub != "" {
+		err = fmt.Errorf("%w: %q has sub-entries, so can't become a sub-entry", ErrInvalidID, from.String())
+		return
+	}
+
+	// check everything first, so a failure leaves the index as it was
+	if err = data.checkEntryID(to); err != nil {
+		return
+	}
+
+	for _, subID := range subs {
+		id := to.EntryID()
+		id.Sub = subID.Sub
+		if err = data.validID(id); err != nil {
+			return
+		}
+	}
+
+	notes := map[string][]ACID{}
+	for _, id := range append([]ACID{from}, subs...) {
+		notes[id.String()] = data.notes[id.String()]
+	}
+
+	moved := map[string]ACID{from.String(): to}
+
+	entry.ID = to
+	entry.Modified = now()
+	err = data.loadEntry(entry)
+	if err != nil {
+		return
+	}
+
+	for _, subID := range subs {
+		sub := data.entries[subID.String()]
+		sub.ID = to.EntryID()
+		sub.ID.Sub = subID.Sub
+		sub.Modified = entry.Modified
+		moved[subID.String()] = sub.ID
+
+		err = data.loadEntry(sub)
+		if err != nil {
+			return
+		}
+	}
+
+	dangling, err = data.removeEntry(from)
+	if err != nil {
+		return
+	}
+
+	for old, id := range moved {
+		if notes[old] != nil {
+			data.notes[id.String()] = notes[old]
+		}
+	}
+
+	return
+}
@@ -0,0 +1,114 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex_test
+
+import (
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLinkedIndex() (index *jdex.Index, account jdex.ACID, contract jdex.ACID) {
+	index, _ = jdex.NewIndex()
+	account = jdex.MustParseACID("11.02")
+	contract = jdex.MustParseACID("12.04")
+
+	index.PutArea(account, "Finance")
+	index.PutCategory(account, "Accounts")
+	index.PutCategory(contract, "Contracts")
+	index.PutEntry(jdex.Entry{ID: account, Name: "Current"})
+	index.PutEntry(jdex.Entry{
+		ID:   contract,
+		Name: "Phone",
+		Metadata: map[string]jdex.Value{
+			"Paid from": jdex.ACIDValue(account),
+		},
+	})
+
+	return
+}
+
+func TestFindWikiLinks(t *testing.T) {
+	ids := jdex.FindWikiLinks("Paid from [[11.02]], see [[12.04|the contract]] and [[11.02]] again. [[not an id]]")
+
+	assert.Equal(t, []jdex.ACID{
+		jdex.MustParseACID("11.02"),
+		jdex.MustParseACID("12.04"),
+	}, ids)
+}
+
+func Test_Index_Backlinks(t *testing.T) {
+	index, account, contract := newLinkedIndex()
+	index.PutNoteLinks(account, []jdex.ACID{contract})
+
+	assert.Equal(t, []jdex.Link{
+		{From: contract, To: account, Key: "Paid from"},
+	}, index.Backlinks(account))
+	assert.Equal(t, []jdex.Link{
+		{From: account, To: contract},
+	}, index.Backlinks(contract))
+}
+
+func Test_Index_RemoveEntry_ReportsDangling(t *testing.T) {
+	index, account, contract := newLinkedIndex()
+
+	dangling, err := index.RemoveEntry(account)
+	assert.NoError(t, err)
+	assert.Equal(t, []jdex.Link{
+		{From: contract, To: account, Key: "Paid from"},
+	}, dangling)
+	assert.Equal(t, dangling, index.DanglingLinks())
+
+	_, err = index.Entry(account)
+	assert.ErrorIs(t, err, jdex.ErrEntryNotFound)
+}
+
+func Test_Index_RenumberEntry(t *testing.T) {
+	index, account, contract := newLinkedIndex()
+	renumbered := jdex.MustParseACID("11.03")
+
+	dangling, err := index.RenumberEntry(account, renumbered)
+	assert.NoError(t, err)
+	assert.Len(t, dangling, 1)
+
+	entry, err := index.Entry(renumbered)
+	assert.NoError(t, err)
+	assert.Equal(t, "Current", entry.Name)
+
+	_, err = index.RenumberEntry(renumbered, contract)
+	assert.ErrorIs(t, err, jdex.ErrEntryExists)
+
+	system := jdex.MustParseACID("00.00")
+	_, err = index.RenumberEntry(system, jdex.MustParseACID("11.05"))
+	assert.ErrorIs(t, err, jdex.ErrProtectedID)
+	_, err = index.RenumberEntry(renumbered, system)
+	assert.ErrorIs(t, err, jdex.ErrProtectedID)
+
+	entry, err = index.Entry(system)
+	assert.NoError(t, err)
+	assert.Equal(t, "System Index", entry.Name)
+
+	_, err = index.RenumberEntry(renumbered, jdex.MustParseACID("11.03+001"))
+	assert.ErrorIs(t, err, jdex.ErrInvalidID)
+	_, err = index.RenumberEntry(renumbered, jdex.MustParseACID("19.01"))
+	assert.ErrorIs(t, err, jdex.ErrCategoryNotFound)
+
+	entry, err = index.Entry(renumbered)
+	assert.NoError(t, err)
+	assert.Equal(t, "Current", entry.Name)
+}
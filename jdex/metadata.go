@@ -0,0 +1,187 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex
+
+import (
+	"errors"
+	"maps"
+)
+
+// Set the metadata of an area, such as its description or owner. Entries in
+// the area inherit it.
+func (index *Index) PutAreaMetadata(id ACID, metadata map[string]Value) error {
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().putAreaMetadata(id, metadata)
+}
+
+func (data *indexData) putAreaMetadata(id ACID, metadata map[string]Value) error {
+	if err := id.ValidLocal(); err != nil {
+		return errors.Join(ErrInvalidID, err)
+	}
+
+	area, ok := data.areas[id.Area]
+	if !ok {
+		return ErrAreaNotFound
+	}
+
+	data.emitMetadata(id.AreaID(), area.name, nil, nil, area.metadata, metadata)
+	area.metadata = maps.Clone(metadata)
+	data.areas[id.Area] = area
+	return nil
+}
+
+// Set the metadata of a category. Entries in the category inherit it.
+func (index *Index) PutCategoryMetadata(id ACID, metadata map[string]Value) error {
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().putCategoryMetadata(id, metadata)
+}
+
+func (data *indexData) putCategoryMetadata(id ACID, metadata map[string]Value) error {
+	if err := id.ValidLocal(); err != nil {
+		return errors.Join(ErrInvalidID, err)
+	}
+
+	area, ok := data.areas[id.Area]
+	if !ok {
+		return ErrAreaNotFound
+	}
+
+	category, ok := area.categories[id.Category]
+	if !ok {
+		return ErrCategoryNotFound
+	}
+
+	data.emitMetadata(id.CategoryID(), category.name, nil, nil, category.metadata, metadata)
+	category.metadata = maps.Clone(metadata)
+	area.categories[id.Category] = category
+	return nil
+}
+
+// Get the metadata set on an area.
+func (index *Index) AreaMetadata(id ACID) (metadata map[string]Value, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.areaMetadata(id)
+}
+
+func (data *indexData) areaMetadata(id ACID) (metadata map[string]Value, err error) {
+	area, ok := data.areas[id.Area]
+	if !ok {
+		err = ErrAreaNotFound
+		return
+	}
+
+	return maps.Clone(area.metadata), nil
+}
+
+// Get the metadata set on a category, without the area's.
+func (index *Index) CategoryMetadata(id ACID) (metadata map[string]Value, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.categoryMetadata(id)
+}
+
+func (data *indexData) categoryMetadata(id ACID) (metadata map[string]Value, err error) {
+	area, ok := data.areas[id.Area]
+	if !ok {
+		err = ErrAreaNotFound
+		return
+	}
+
+	category, ok := area.categories[id.Category]
+	if !ok {
+		err = ErrCategoryNotFound
+		return
+	}
+
+	return maps.Clone(category.metadata), nil
+}
+
+// Get the metadata of an area, category or entry along with what it inherits.
+// Entry values override category values, which override area values.
+// Sub-entry values override those of their parent entry.
+func (index *Index) EffectiveMetadata(id ACID) (metadata map[string]Value, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.effectiveMetadata(id)
+}
+
+func (data *indexData) effectiveMetadata(id ACID) (metadata map[string]Value, err error) {
+	switch id.Level() {
+	case LevelArea:
+		var areaMetadata map[string]Value
+		areaMetadata, err = data.areaMetadata(id)
+		if err != nil {
+			return
+		}
+
+		metadata = map[string]Value{}
+		maps.Copy(metadata, areaMetadata)
+		return
+	case LevelCategory:
+		return data.inheritedMetadata(id)
+	}
+
+	entry, err := data.entry(id)
+	if err != nil {
+		return
+	}
+
+	metadata, err = data.inheritedMetadata(id)
+	if err != nil {
+		return
+	}
+
+	maps.Copy(metadata, entry.Metadata)
+	return
+}
+
+// Get the metadata an entry in the category inherits. Sub-entries also
+// inherit their parent entry's metadata.
+func (data *indexData) inheritedMetadata(id ACID) (metadata map[string]Value, err error) {
+	areaMetadata, err := data.areaMetadata(id)
+	if err != nil {
+		return
+	}
+
+	categoryMetadata, err := data.categoryMetadata(id)
+	if err != nil {
+		return
+	}
+
+	metadata = map[string]Value{}
+	maps.Copy(metadata, areaMetadata)
+	maps.Copy(metadata, categoryMetadata)
+
+	if id.Sub != "" {
+		parent, parentErr := data.entry(id.EntryID())
+		if parentErr != nil {
+			return nil, parentErr
+		}
+
+		maps.Copy(metadata, parent.Metadata)
+	}
+
+	return
+}
@@ -0,0 +1,82 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex_test
+
+import (
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Index_EffectiveMetadata_Inherits(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	id := jdex.MustParseACID("12.01")
+
+	index.PutArea(id, "Finance")
+	index.PutCategory(id, "Invoices")
+	index.PutAreaMetadata(id, map[string]jdex.Value{
+		"Owner":  jdex.StringValue("Razza"),
+		"Vendor": jdex.StringValue("Nobody"),
+	})
+	index.PutCategoryMetadata(id, map[string]jdex.Value{
+		"Vendor": jdex.StringValue("Default Co"),
+	})
+	index.PutEntry(jdex.Entry{
+		ID:       id,
+		Metadata: map[string]jdex.Value{"Vendor": jdex.StringValue("Acme")},
+	})
+
+	metadata, err := index.EffectiveMetadata(id)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]jdex.Value{
+		"Owner":  jdex.StringValue("Razza"),
+		"Vendor": jdex.StringValue("Acme"),
+	}, metadata)
+
+	entry, _ := index.Entry(id)
+	assert.Equal(t, map[string]jdex.Value{
+		"Vendor": jdex.StringValue("Acme"),
+	}, entry.Metadata)
+}
+
+func Test_Index_PutEntry_InheritedSatisfiesSchema(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	id := jdex.MustParseACID("12.01")
+
+	index.PutArea(id, "Finance")
+	index.PutCategory(id, "Invoices")
+	index.PutAreaSchema(id, jdex.Schema{
+		"Owner": {Key: "Owner", Required: true},
+	})
+	index.PutAreaMetadata(id, map[string]jdex.Value{
+		"Owner": jdex.StringValue("Razza"),
+	})
+
+	err := index.PutEntry(jdex.Entry{ID: id})
+	assert.NoError(t, err)
+}
+
+func Test_Index_PutCategoryMetadata_FailNoCategory(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	id := jdex.MustParseACID("12.01")
+
+	index.PutArea(id, "Finance")
+	err := index.PutCategoryMetadata(id, map[string]jdex.Value{})
+
+	assert.ErrorIs(t, err, jdex.ErrCategoryNotFound)
+}
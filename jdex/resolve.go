@@ -0,0 +1,198 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Something input might refer to, and how well it matched.
+type Candidate struct {
+	ID    ACID
+	Name  string
+	Score int // Higher is better, see the Score constants.
+}
+
+// How well a name matches, from best to worst. Fuzzy matches score up to
+// ScoreFuzzy, less the number of characters skipped over.
+const (
+	ScoreExact      = 100 // The whole name, ignoring case.
+	ScorePrefix     = 80  // The start of the name.
+	ScoreWordPrefix = 60  // The start of a word in the name.
+	ScoreSubstring  = 50  // Anywhere in the name.
+	ScoreFuzzy      = 40  // The characters in order, with others between.
+)
+
+var ErrNoMatch = errors.New("nothing matches")
+var ErrAmbiguous = errors.New("more than one thing matches")
+var ErrNoBase = errors.New("relative ID needs a location to be relative to")
+
+// Work out what some input refers to. It can be an exact ID, such as `11.03`;
+// an ID relative to base, such as `.03` for an entry in base's category or
+// `+001` for a sub-entry of base; or part of a name.
+//
+// Names are matched against every area, category and entry, returning the
+// candidates best first. Names which also read as IDs, such as `IRS` or
+// `2024`, are matched as names when there's nothing with that ID. When one
+// candidate stands out, it's also returned as id. Otherwise, err is
+// ErrAmbiguous and the candidates are left to pick from.
+func (index *Index) Resolve(input string, base ACID) (id ACID, candidates []Candidate, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.resolve(input, base)
+}
+
+func (data *indexData) resolve(input string, base ACID) (id ACID, candidates []Candidate, err error) {
+	input = strings.TrimSpace(input)
+
+	if strings.HasPrefix(input, ".") || strings.HasPrefix(input, "+") {
+		id, err = resolveRelative(input, base)
+		if err != nil {
+			return
+		}
+
+		return data.resolveExact(id)
+	}
+
+	var exactErr error
+	if exact, _, parseErr := ParseID(input); parseErr == nil {
+		id, candidates, exactErr = data.resolveExact(exact)
+		if exactErr == nil {
+			return
+		}
+	}
+
+	candidates = data.matchNames(input)
+	switch {
+	case len(candidates) == 0 && exactErr != nil:
+		err = exactErr
+	case len(candidates) == 0:
+		err = fmt.Errorf("%w: %q", ErrNoMatch, input)
+	case len(candidates) == 1,
+		candidates[0].Score >= ScoreSubstring && candidates[0].Score > candidates[1].Score:
+		id = candidates[0].ID
+	default:
+		err = fmt.Errorf("%w: %q", ErrAmbiguous, input)
+	}
+
+	return
+}
+
+func (data *indexData) resolveExact(id ACID) (ACID, []Candidate, error) {
+	name, err := data.name(id)
+	if err != nil {
+		return ACID{}, nil, fmt.Errorf("%s: %w", id.String(), err)
+	}
+
+	return id, []Candidate{{ID: id, Name: name, Score: ScoreExact}}, nil
+}
+
+// Turn `.03` or `+001` into a full ID, going by base.
+func resolveRelative(input string, base ACID) (id ACID, err error) {
+	if strings.HasPrefix(input, "+") {
+		if base.Entry == "" {
+			err = fmt.Errorf("%w: %q needs an entry", ErrNoBase, input)
+			return
+		}
+
+		entryID := base.EntryID()
+		return ParseACID(entryID.String() + input)
+	}
+
+	if base.Category == "" {
+		err = fmt.Errorf("%w: %q needs a category", ErrNoBase, input)
+		return
+	}
+
+	return ParseACID(base.CategoryString() + input)
+}
+
+// Score every area, category and entry name against input, best first.
+func (data *indexData) matchNames(input string) (candidates []Candidate) {
+	consider := func(id ACID, name string) {
+		if score, ok := scoreName(input, name); ok {
+			candidates = append(candidates, Candidate{ID: id, Name: name, Score: score})
+		}
+	}
+
+	for id := range data.walk(ACID{}) {
+		name, _ := data.name(id)
+		consider(id, name)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b Candidate) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), a.ID.Compare(b.ID))
+	})
+	return
+}
+
+// Score how well input matches a name, ignoring case.
+func scoreName(input string, name string) (score int, ok bool) {
+	input = strings.ToLower(input)
+	name = strings.ToLower(name)
+
+	if input == "" {
+		return
+	}
+
+	switch {
+	case name == input:
+		return ScoreExact, true
+	case strings.HasPrefix(name, input):
+		return ScorePrefix, true
+	case hasWordPrefix(name, input):
+		return ScoreWordPrefix, true
+	case strings.Contains(name, input):
+		return ScoreSubstring, true
+	}
+
+	skipped := 0
+	rest := name
+	for n, r := range input {
+		i := strings.IndexRune(rest, r)
+		if i < 0 {
+			return
+		}
+
+		if n > 0 {
+			skipped += utf8.RuneCountInString(rest[:i])
+		}
+
+		rest = rest[i+utf8.RuneLen(r):]
+	}
+
+	return max(ScoreFuzzy-skipped, 1), true
+}
+
+func hasWordPrefix(name string, prefix string) bool {
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,142 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex_test
+
+import (
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/stretchr/testify/assert"
+)
+
+func newResolveIndex(t *testing.T) *jdex.Index {
+	index, _ := jdex.NewIndex()
+	index.PutArea(jdex.ACID{Area: '1'}, "Finance")
+	index.PutCategory(jdex.ACID{Area: '1', Category: "1"}, "Invoices")
+	index.PutCategory(jdex.ACID{Area: '1', Category: "2"}, "Receipts")
+
+	for _, entry := range [][2]string{
+		{"11.01", "Acme Corp"},
+		{"11.02", "Acme Holdings"},
+		{"11.03", "Globex"},
+		{"11.03+001", "Globex Quarterly"},
+		{"12.01", "Groceries"},
+	} {
+		err := index.PutEntry(jdex.Entry{ID: jdex.MustParseACID(entry[0]), Name: entry[1]})
+		if !assert.NoError(t, err, entry[0]) {
+			t.FailNow()
+		}
+	}
+
+	return index
+}
+
+func Test_Index_Resolve_Exact(t *testing.T) {
+	index := newResolveIndex(t)
+
+	for _, input := range []string{"10-19", "11", "11.03", "11.03+001"} {
+		expected, _, _ := jdex.ParseID(input)
+		id, _, err := index.Resolve(input, jdex.ACID{})
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, id, input)
+	}
+
+	_, _, err := index.Resolve("11.09", jdex.ACID{})
+	assert.ErrorIs(t, err, jdex.ErrEntryNotFound)
+}
+
+func Test_Index_Resolve_NameLikeID(t *testing.T) {
+	index := newResolveIndex(t)
+	index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("12.02"), Name: "IRS"})
+	index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("12.03"), Name: "2024"})
+
+	for input, expected := range map[string]string{"IRS": "12.02", "irs": "12.02", "2024": "12.03"} {
+		id, _, err := index.Resolve(input, jdex.ACID{})
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, id.String(), input)
+	}
+
+	// IDs in the index still come first
+	id, _, err := index.Resolve("11", jdex.ACID{})
+	assert.NoError(t, err)
+	assert.Equal(t, "11", id.String())
+
+	_, _, err = index.Resolve("Q1", jdex.ACID{})
+	assert.ErrorIs(t, err, jdex.ErrAreaNotFound)
+}
+
+func Test_Index_Resolve_Relative(t *testing.T) {
+	index := newResolveIndex(t)
+	base := jdex.MustParseACID("11.01")
+
+	id, _, err := index.Resolve(".03", base)
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.MustParseACID("11.03"), id)
+
+	id, _, err = index.Resolve("+001", jdex.MustParseACID("11.03"))
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.MustParseACID("11.03+001"), id)
+
+	_, _, err = index.Resolve(".03", jdex.ACID{})
+	assert.ErrorIs(t, err, jdex.ErrNoBase)
+
+	_, _, err = index.Resolve("+001", jdex.ACID{Area: '1', Category: "1"})
+	assert.ErrorIs(t, err, jdex.ErrNoBase)
+}
+
+func Test_Index_Resolve_Name(t *testing.T) {
+	index := newResolveIndex(t)
+
+	id, _, err := index.Resolve("globex", jdex.ACID{})
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.MustParseACID("11.03"), id)
+
+	id, _, err = index.Resolve("Receipts", jdex.ACID{})
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.ACID{Area: '1', Category: "2"}, id)
+
+	id, _, err = index.Resolve("holdings", jdex.ACID{})
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.MustParseACID("11.02"), id)
+
+	_, _, err = index.Resolve("initech", jdex.ACID{})
+	assert.ErrorIs(t, err, jdex.ErrNoMatch)
+}
+
+func Test_Index_Resolve_Ambiguous(t *testing.T) {
+	index := newResolveIndex(t)
+
+	_, candidates, err := index.Resolve("acme", jdex.ACID{})
+	assert.ErrorIs(t, err, jdex.ErrAmbiguous)
+	if assert.Len(t, candidates, 2) {
+		assert.Equal(t, jdex.MustParseACID("11.01"), candidates[0].ID)
+		assert.Equal(t, jdex.MustParseACID("11.02"), candidates[1].ID)
+		assert.Equal(t, jdex.ScorePrefix, candidates[0].Score)
+	}
+}
+
+func Test_Index_Resolve_Fuzzy(t *testing.T) {
+	index := newResolveIndex(t)
+
+	id, candidates, err := index.Resolve("grcr", jdex.ACID{})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, jdex.MustParseACID("12.01"), id)
+	assert.Less(t, candidates[0].Score, jdex.ScoreSubstring)
+}
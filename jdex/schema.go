@@ -0,0 +1,272 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Schema describes the metadata entries in an area or category should have,
+// keyed by metadata key.
+type Schema map[string]Field
+
+// A single metadata key in a schema.
+type Field struct {
+	Key      string    // Metadata key.
+	Type     ValueType // Type values are converted to.
+	Required bool      // Whether entries must have the key.
+	Allowed  []string  // If set, the only values the key may have.
+	Default  *Value    // Filled in when an entry doesn't have the key.
+}
+
+// An entry which doesn't conform to its schema.
+type Violation struct {
+	ID  ACID
+	Err error
+}
+
+var ErrSchemaViolation = errors.New("entry does not conform to its schema")
+
+// Returns the keys in the schema, sorted.
+func (schema Schema) Keys() []string {
+	return slices.Sorted(maps.Keys(schema))
+}
+
+// Returns a copy of the schema with other's fields taking precedence.
+func (schema Schema) Merge(other Schema) Schema {
+	merged := maps.Clone(schema)
+	if merged == nil {
+		merged = Schema{}
+	}
+
+	maps.Copy(merged, other)
+	return merged
+}
+
+// Checks metadata against the schema, returning a copy with defaults filled
+// in and values converted to their declared types. Required keys may instead
+// be inherited from the entry's area or category, as long as the inherited
+// value fits the field too.
+func (schema Schema) Apply(metadata map[string]Value, inherited map[string]Value) (applied map[string]Value, err error) {
+	applied = maps.Clone(metadata)
+	if applied == nil {
+		applied = map[string]Value{}
+	}
+
+	var errs []error
+	for _, key := range schema.Keys() {
+		field := schema[key]
+
+		value, ok := applied[key]
+		if !ok {
+			if value, ok := inherited[key]; ok {
+				if _, err := field.Convert(value); err != nil {
+					errs = append(errs, fmt.Errorf("%w: inherited %q: %w", ErrSchemaViolation, key, err))
+				}
+				continue
+			}
+
+			if field.Default != nil {
+				applied[key] = *field.Default
+			} else if field.Required {
+				errs = append(errs, fmt.Errorf("%w: missing required key %q", ErrSchemaViolation, key))
+			}
+			continue
+		}
+
+		value, err := field.Convert(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %q: %w", ErrSchemaViolation, key, err))
+			continue
+		}
+
+		applied[key] = value
+	}
+
+	return applied, errors.Join(errs...)
+}
+
+// Converts a value to the field's type and checks it is allowed.
+func (field *Field) Convert(value Value) (converted Value, err error) {
+	converted = value
+	if value.Type != field.Type {
+		converted, err = NewValue(field.Type, value.String())
+		if err != nil {
+			return
+		}
+	}
+
+	if len(field.Allowed) > 0 && !slices.Contains(field.Allowed, converted.String()) {
+		err = fmt.Errorf("%q is not one of %q", converted.String(), field.Allowed)
+	}
+
+	return
+}
+
+// Set the schema for entries in an area.
+func (index *Index) PutAreaSchema(id ACID, schema Schema) error {
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().putAreaSchema(id, schema)
+}
+
+func (data *indexData) putAreaSchema(id ACID, schema Schema) error {
+	if err := id.ValidLocal(); err != nil {
+		return errors.Join(ErrInvalidID, err)
+	}
+
+	area, ok := data.areas[id.Area]
+	if !ok {
+		return ErrAreaNotFound
+	}
+
+	area.schema = maps.Clone(schema)
+	data.areas[id.Area] = area
+	return nil
+}
+
+// Set the schema for entries in a category.
+func (index *Index) PutCategorySchema(id ACID, schema Schema) error {
+	index.mu.Lock()
+	defer index.unlock()
+
+	return index.writable().putCategorySchema(id, schema)
+}
+
+func (data *indexData) putCategorySchema(id ACID, schema Schema) error {
+	if err := id.ValidLocal(); err != nil {
+		return errors.Join(ErrInvalidID, err)
+	}
+
+	area, ok := data.areas[id.Area]
+	if !ok {
+		return ErrAreaNotFound
+	}
+
+	category, ok := area.categories[id.Category]
+	if !ok {
+		return ErrCategoryNotFound
+	}
+
+	category.schema = maps.Clone(schema)
+	area.categories[id.Category] = category
+	return nil
+}
+
+// Get the schema declared on an area.
+func (index *Index) AreaSchema(id ACID) (schema Schema, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.areaSchema(id)
+}
+
+func (data *indexData) areaSchema(id ACID) (schema Schema, err error) {
+	area, ok := data.areas[id.Area]
+	if !ok {
+		err = ErrAreaNotFound
+		return
+	}
+
+	return maps.Clone(area.schema), nil
+}
+
+// Get the schema declared on a category, without the area's.
+func (index *Index) CategorySchema(id ACID) (schema Schema, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.categorySchema(id)
+}
+
+func (data *indexData) categorySchema(id ACID) (schema Schema, err error) {
+	area, ok := data.areas[id.Area]
+	if !ok {
+		err = ErrAreaNotFound
+		return
+	}
+
+	category, ok := area.categories[id.Category]
+	if !ok {
+		err = ErrCategoryNotFound
+		return
+	}
+
+	return maps.Clone(category.schema), nil
+}
+
+// Get the schema entries in a category are checked against. Fields declared
+// on the category override those declared on its area.
+func (index *Index) Schema(id ACID) (schema Schema, err error) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.schema(id)
+}
+
+func (data *indexData) schema(id ACID) (schema Schema, err error) {
+	areaSchema, err := data.areaSchema(id)
+	if err != nil {
+		return
+	}
+
+	categorySchema, err := data.categorySchema(id)
+	if err != nil {
+		return
+	}
+
+	return areaSchema.Merge(categorySchema), nil
+}
+
+// Lists the entries which don't conform to their schema.
+func (index *Index) Validate() (violations []Violation) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.validate()
+}
+
+func (data *indexData) validate() (violations []Violation) {
+	for _, entry := range data.entriesIn(nil) {
+		_, err := data.conform(entry)
+		if err != nil {
+			violations = append(violations, Violation{ID: entry.ID, Err: err})
+		}
+	}
+
+	return
+}
+
+// Checks an entry against its schema, returning its metadata with defaults
+// filled in and values converted.
+func (data *indexData) conform(entry Entry) (metadata map[string]Value, err error) {
+	schema, err := data.schema(entry.ID)
+	if err != nil {
+		return
+	}
+
+	inherited, err := data.inheritedMetadata(entry.ID)
+	if err != nil {
+		return
+	}
+
+	return schema.Apply(entry.Metadata, inherited)
+}
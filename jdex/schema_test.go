@@ -0,0 +1,122 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex_test
+
+import (
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/stretchr/testify/assert"
+)
+
+func newInvoicesIndex(t *testing.T) *jdex.Index {
+	index, _ := jdex.NewIndex()
+	id := jdex.MustParseACID("12.01")
+
+	open := jdex.StringValue("open")
+	index.PutArea(id, "Finance")
+	index.PutCategory(id, "Invoices")
+	err := index.PutCategorySchema(id, jdex.Schema{
+		"Amount": {Key: "Amount", Type: jdex.TypeDecimal, Required: true},
+		"Status": {Key: "Status", Allowed: []string{"open", "paid"}, Default: &open},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return index
+}
+
+func Test_Index_PutEntry_SchemaDefaultsAndTypes(t *testing.T) {
+	index := newInvoicesIndex(t)
+	id := jdex.MustParseACID("12.01")
+
+	err := index.PutEntry(jdex.Entry{
+		ID:       id,
+		Name:     "Acme",
+		Metadata: map[string]jdex.Value{"Amount": jdex.StringValue("12.50")},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	entry, _ := index.Entry(id)
+	amount, _ := jdex.NewValue(jdex.TypeDecimal, "12.50")
+	assert.Equal(t, map[string]jdex.Value{
+		"Amount": amount,
+		"Status": jdex.StringValue("open"),
+	}, entry.Metadata)
+}
+
+func Test_Index_PutEntry_FailSchema(t *testing.T) {
+	index := newInvoicesIndex(t)
+
+	err := index.PutEntry(jdex.Entry{
+		ID:       jdex.MustParseACID("12.01"),
+		Metadata: map[string]jdex.Value{"Status": jdex.StringValue("lost")},
+	})
+
+	assert.ErrorIs(t, err, jdex.ErrSchemaViolation)
+	assert.ErrorContains(t, err, `missing required key "Amount"`)
+	assert.ErrorContains(t, err, `"lost" is not one of`)
+}
+
+func Test_Index_PutEntry_SchemaInherited(t *testing.T) {
+	index := newInvoicesIndex(t)
+	id := jdex.MustParseACID("12.01")
+
+	err := index.PutCategoryMetadata(id, map[string]jdex.Value{"Amount": jdex.StringValue("12.50")})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, index.PutEntry(jdex.Entry{ID: id, Name: "Acme"}))
+
+	index.PutCategoryMetadata(id, map[string]jdex.Value{
+		"Amount": jdex.StringValue("lots"),
+		"Status": jdex.StringValue("lost"),
+	})
+	err = index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("12.02"), Name: "Globex"})
+	assert.ErrorIs(t, err, jdex.ErrSchemaViolation)
+	assert.ErrorContains(t, err, `inherited "Amount"`)
+	assert.ErrorContains(t, err, `"lost" is not one of`)
+}
+
+func Test_Index_Validate(t *testing.T) {
+	index := newInvoicesIndex(t)
+	index.LoadEntry(jdex.Entry{ID: jdex.MustParseACID("12.01")})
+
+	violations := index.Validate()
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, jdex.MustParseACID("12.01"), violations[0].ID)
+		assert.ErrorIs(t, violations[0].Err, jdex.ErrSchemaViolation)
+	}
+}
+
+func Test_Index_NextEntryID(t *testing.T) {
+	index := newInvoicesIndex(t)
+	id := jdex.MustParseACID("12.01")
+
+	next, err := index.NextEntryID(id)
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.MustParseACID("12.01"), next)
+
+	index.LoadEntry(jdex.Entry{ID: jdex.MustParseACID("12.07")})
+
+	next, err = index.NextEntryID(id)
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.MustParseACID("12.08"), next)
+}
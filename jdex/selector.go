@@ -0,0 +1,187 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// ACIDSelector picks out entries by their ID.
+type ACIDSelector interface {
+	Match(id ACID) bool
+}
+
+// An inclusive range of IDs. Either both ends are categories, such as `11-13`
+// or `10-19` for a whole area, or both are entries, such as `11.01-11.20`.
+type ACIDRange struct {
+	From ACID
+	To   ACID
+}
+
+// A glob pattern over IDs, such as `11.*` or `1*.0*`. Patterns without a dot
+// match categories, so `1*` selects everything in area 10-19.
+type ACIDPattern string
+
+var ErrParseACIDRange = errors.New("range is expected to be two categories or two entries separated by a dash")
+
+// Parse a range of categories or entries.
+func ParseACIDRange(input string) (r ACIDRange, err error) {
+	from, to, ok := strings.Cut(input, "-")
+	if !ok || strings.Contains(to, "-") {
+		err = ErrParseACIDRange
+		return
+	}
+
+	fromEntry := strings.Contains(from, ".")
+	if fromEntry != strings.Contains(to, ".") {
+		err = ErrParseACIDRange
+		return
+	}
+
+	if fromEntry {
+		if r.From, err = ParseACID(from); err != nil {
+			return
+		}
+		if r.To, err = ParseACID(to); err != nil {
+			return
+		}
+	} else {
+		if r.From, err = parseCategoryACID(from); err != nil {
+			return
+		}
+		if r.To, err = parseCategoryACID(to); err != nil {
+			return
+		}
+	}
+
+	if r.To.Less(r.From) {
+		err = ErrParseACIDRange
+	}
+
+	return
+}
+
+func parseCategoryACID(input string) (id ACID, err error) {
+	id, level, err := ParseID(input)
+	if err == nil && level != LevelCategory {
+		err = ErrParseACIDRange
+	}
+
+	return
+}
+
+func (r ACIDRange) Match(id ACID) bool {
+	if id.System != r.From.System {
+		return false
+	}
+
+	id = ACID{System: id.System, Area: id.Area, Category: id.Category, Entry: id.Entry}
+	if r.From.Entry == "" {
+		id.Entry = ""
+	}
+
+	return !id.Less(r.From) && !r.To.Less(id)
+}
+
+// Parse a glob pattern, where `*` matches any run of characters and `?`
+// matches exactly one.
+func ParseACIDPattern(input string) (p ACIDPattern, err error) {
+	for _, c := range input {
+		if !strings.ContainsRune(ACIDCharset+"*?.+", c) {
+			err = ErrACIDInvalidChars
+			return
+		}
+	}
+
+	if _, err = path.Match(input, ""); err != nil {
+		return
+	}
+
+	return ACIDPattern(input), nil
+}
+
+func (p ACIDPattern) Match(id ACID) bool {
+	target := id.CategoryString()
+	if strings.Contains(string(p), ".") {
+		target = id.String()
+	}
+
+	matched, _ := path.Match(string(p), target)
+	return matched
+}
+
+// Get a selector for an area or category, selecting every entry in it, or
+// for an entry. One with a system code only selects that system's entries.
+func IDSelector(id ACID) ACIDSelector {
+	if id.System != "" {
+		// patterns only see the system code when matching whole IDs
+		switch id.Level() {
+		case LevelArea:
+			return ACIDPattern(id.System + "." + string(id.Area) + "*.*")
+		case LevelCategory:
+			return ACIDPattern(id.System + "." + id.CategoryString() + ".*")
+		}
+
+		return ACIDPattern(id.String())
+	}
+
+	switch id.Level() {
+	case LevelArea:
+		return ACIDPattern(string(id.Area) + "*")
+	case LevelCategory:
+		return ACIDPattern(id.CategoryString())
+	}
+
+	return ACIDPattern(id.String())
+}
+
+// Parse an ID, range or pattern into a selector. An area or category selects
+// every entry in it.
+func ParseACIDSelector(input string) (ACIDSelector, error) {
+	if strings.ContainsAny(input, "*?") {
+		return ParseACIDPattern(input)
+	}
+
+	id, _, err := ParseID(input)
+	if err == nil {
+		return IDSelector(id), nil
+	}
+
+	if strings.Contains(input, "-") {
+		return ParseACIDRange(input)
+	}
+
+	return nil, errors.Join(ErrInvalidID, err)
+}
+
+// Get the entries picked out by a selector, in order.
+func (index *Index) Select(selector ACIDSelector) (ids []ACID) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.selectIDs(selector)
+}
+
+func (data *indexData) selectIDs(selector ACIDSelector) (ids []ACID) {
+	for id := range data.entriesIn(selector) {
+		ids = append(ids, id)
+	}
+
+	return
+}
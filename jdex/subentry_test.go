@@ -0,0 +1,126 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdex/jdexfile"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeSubEntryIndex(t *testing.T) *jdex.Index {
+	index, _ := jdex.NewIndex()
+	id := jdex.MustParseACID("11.01")
+	index.PutArea(id, "Finance")
+	index.PutCategory(id, "Clients")
+	if err := index.PutEntry(jdex.Entry{ID: id, Name: "Acme", Metadata: map[string]jdex.Value{
+		"Client": jdex.StringValue("Acme"),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range []string{"11.01+001", "11.01+VLD"} {
+		if err := index.PutEntry(jdex.Entry{ID: jdex.MustParseACID(input), Name: "Invoice"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return index
+}
+
+func Test_Index_SubEntries(t *testing.T) {
+	index := makeSubEntryIndex(t)
+
+	subs, ok := index.SubEntries(jdex.MustParseACID("11.01"))
+	assert.True(t, ok)
+	assert.Equal(t, []jdex.ACID{
+		jdex.MustParseACID("11.01+001"),
+		jdex.MustParseACID("11.01+VLD"),
+	}, subs)
+
+	entries, _ := index.Entries(jdex.MustParseACID("11.01"))
+	assert.Equal(t, []jdex.ACID{jdex.MustParseACID("11.01")}, entries)
+
+	metadata, err := index.EffectiveMetadata(jdex.MustParseACID("11.01+001"))
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.StringValue("Acme"), metadata["Client"])
+}
+
+func Test_Index_PutEntry_FailSubWithoutParent(t *testing.T) {
+	index := makeSubEntryIndex(t)
+
+	err := index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.02+001")})
+	assert.ErrorIs(t, err, jdex.ErrEntryNotFound)
+}
+
+func Test_Index_NextSubID(t *testing.T) {
+	index := makeSubEntryIndex(t)
+
+	next, err := index.NextSubID(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+	assert.Equal(t, "11.01+002", next.String())
+}
+
+func Test_Index_RenumberEntry_MovesSubEntries(t *testing.T) {
+	index := makeSubEntryIndex(t)
+
+	_, err := index.RenumberEntry(jdex.MustParseACID("11.01"), jdex.MustParseACID("11.05"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	subs, _ := index.SubEntries(jdex.MustParseACID("11.05"))
+	assert.Equal(t, []jdex.ACID{
+		jdex.MustParseACID("11.05+001"),
+		jdex.MustParseACID("11.05+VLD"),
+	}, subs)
+
+	_, err = index.Entry(jdex.MustParseACID("11.01+001"))
+	assert.ErrorIs(t, err, jdex.ErrEntryNotFound)
+}
+
+func Test_Index_RemoveEntry_RemovesSubEntries(t *testing.T) {
+	index := makeSubEntryIndex(t)
+
+	_, err := index.RemoveEntry(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+
+	_, err = index.Entry(jdex.MustParseACID("11.01+001"))
+	assert.ErrorIs(t, err, jdex.ErrEntryNotFound)
+}
+
+func TestJdexfile_SubEntries_RoundTrip(t *testing.T) {
+	index := makeSubEntryIndex(t)
+
+	var buffer bytes.Buffer
+	if !assert.NoError(t, jdexfile.Write(index, &buffer)) {
+		t.FailNow()
+	}
+
+	assert.Contains(t, buffer.String(), "\n      11.01+001 Invoice\n")
+
+	read, err := jdexfile.Read(&buffer)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	subs, _ := read.SubEntries(jdex.MustParseACID("11.01"))
+	assert.Len(t, subs, 2)
+}
@@ -0,0 +1,120 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Tags are made of letters, numbers, `_`, `-` and `/`, with at least one
+// letter so numbers such as `#42` aren't taken for tags.
+var tagRegex = regexp.MustCompile(`^[\p{N}_\-/]*\p{L}[\p{L}\p{N}_\-/]*$`)
+
+var ErrInvalidTag = errors.New("tag is invalid")
+
+// Normalise a tag, with or without its leading `#`, to lower case.
+func NormaliseTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+	if !tagRegex.MatchString(tag) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+	}
+
+	return tag, nil
+}
+
+// Normalise tags, sorting them and dropping duplicates.
+func NormaliseTags(tags []string) (normalised []string, err error) {
+	for _, tag := range tags {
+		tag, err = NormaliseTag(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		normalised = append(normalised, tag)
+	}
+
+	slices.Sort(normalised)
+	return slices.Compact(normalised), nil
+}
+
+func (entry *Entry) HasTag(tag string) bool {
+	tag, err := NormaliseTag(tag)
+	if err != nil {
+		return false
+	}
+
+	_, found := slices.BinarySearch(entry.Tags, tag)
+	return found
+}
+
+// Returns every tag in use, sorted.
+func (index *Index) Tags() []string {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.tagNames()
+}
+
+func (data *indexData) tagNames() []string {
+	return slices.Sorted(maps.Keys(data.tags))
+}
+
+// Returns the entries with a tag, sorted.
+func (index *Index) Tagged(tag string) (ids []ACID) {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	return index.data.tagged(tag)
+}
+
+func (data *indexData) tagged(tag string) (ids []ACID) {
+	tag, err := NormaliseTag(tag)
+	if err != nil {
+		return
+	}
+
+	for key := range data.tags[tag] {
+		ids = append(ids, data.entries[key].ID)
+	}
+
+	slices.SortFunc(ids, CompareACIDs)
+	return
+}
+
+func (data *indexData) tagEntry(entry Entry) {
+	for _, tag := range entry.Tags {
+		if data.tags[tag] == nil {
+			data.tags[tag] = map[string]bool{}
+		}
+
+		data.tags[tag][entry.ID.String()] = true
+	}
+}
+
+func (data *indexData) untagEntry(entry Entry) {
+	for _, tag := range entry.Tags {
+		delete(data.tags[tag], entry.ID.String())
+		if len(data.tags[tag]) == 0 {
+			delete(data.tags, tag)
+		}
+	}
+}
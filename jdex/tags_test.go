@@ -0,0 +1,55 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex_test
+
+import (
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormaliseTags(t *testing.T) {
+	tags, err := jdex.NormaliseTags([]string{"#Tax", "bills", "tax"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bills", "tax"}, tags)
+
+	_, err = jdex.NormaliseTags([]string{"two words"})
+	assert.ErrorIs(t, err, jdex.ErrInvalidTag)
+
+	_, err = jdex.NormaliseTags([]string{"#42"})
+	assert.ErrorIs(t, err, jdex.ErrInvalidTag)
+}
+
+func Test_Index_Tagged(t *testing.T) {
+	index, _ := jdex.NewIndex()
+	first := jdex.MustParseACID("12.01")
+	second := jdex.MustParseACID("12.02")
+
+	index.PutArea(first, "Finance")
+	index.PutCategory(first, "Invoices")
+	index.PutEntry(jdex.Entry{ID: first, Tags: []string{"tax", "bills"}})
+	index.PutEntry(jdex.Entry{ID: second, Tags: []string{"tax"}})
+
+	assert.Equal(t, []string{"bills", "tax"}, index.Tags())
+	assert.Equal(t, []jdex.ACID{first, second}, index.Tagged("#TAX"))
+
+	index.PutEntry(jdex.Entry{ID: first, Tags: []string{"tax"}})
+
+	assert.Equal(t, []string{"tax"}, index.Tags())
+	assert.Empty(t, index.Tagged("bills"))
+}
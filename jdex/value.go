@@ -0,0 +1,364 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/big"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type of a metadata value.
+type ValueType int
+
+const (
+	TypeString   ValueType = iota // Free-form text.
+	TypeDate                      // Calendar date, as `2006-01-02`.
+	TypeDateTime                  // Point in time, as RFC 3339.
+	TypeInteger                   // Whole number.
+	TypeDecimal                   // Exact decimal number, such as an amount.
+	TypeBool                      // `true` or `false`.
+	TypeACID                      // Reference to another entry.
+	TypePath                      // Filesystem path.
+)
+
+var valueTypeNames = []string{
+	TypeString:   "string",
+	TypeDate:     "date",
+	TypeDateTime: "datetime",
+	TypeInteger:  "integer",
+	TypeDecimal:  "decimal",
+	TypeBool:     "bool",
+	TypeACID:     "acid",
+	TypePath:     "path",
+}
+
+// Types tried, in order, when a value's type isn't declared.
+var inferredValueTypes = []ValueType{
+	TypeBool,
+	TypeInteger,
+	TypeDecimal,
+	TypeDate,
+	TypeDateTime,
+	TypePath,
+}
+
+const DateLayout = "2006-01-02"
+
+var ErrUnknownValueType = errors.New("unknown metadata type")
+var ErrValueType = errors.New("metadata value is not of the expected type")
+var ErrMetadataNotFound = errors.New("metadata key does not exist")
+
+var decimalRegex = regexp.MustCompile(`^[+-]?\d+(\.(\d+))?$`)
+
+func (t ValueType) String() string {
+	if t < 0 || int(t) >= len(valueTypeNames) {
+		return fmt.Sprintf("ValueType(%d)", int(t))
+	}
+
+	return valueTypeNames[t]
+}
+
+// Parse the name of a metadata type, as used in the jdex format.
+func ParseValueType(name string) (ValueType, error) {
+	for t, typeName := range valueTypeNames {
+		if strings.EqualFold(name, typeName) {
+			return ValueType(t), nil
+		}
+	}
+
+	return TypeString, fmt.Errorf("%w: %q", ErrUnknownValueType, name)
+}
+
+// Value is a single metadata value. It is kept in its canonical text form, so
+// two equal values always compare equal with ==.
+type Value struct {
+	Type ValueType
+	text string
+}
+
+// Parse text as a value of the given type.
+func NewValue(t ValueType, text string) (value Value, err error) {
+	value.Type = t
+
+	switch t {
+	case TypeString:
+		value.text = text
+	case TypeDate:
+		var date time.Time
+		date, err = time.Parse(DateLayout, text)
+		value.text = date.Format(DateLayout)
+	case TypeDateTime:
+		var dateTime time.Time
+		dateTime, err = time.Parse(time.RFC3339, text)
+		value.text = dateTime.Format(time.RFC3339)
+	case TypeInteger:
+		var n int64
+		n, err = strconv.ParseInt(text, 10, 64)
+		value.text = strconv.FormatInt(n, 10)
+	case TypeDecimal:
+		value.text, err = canonicalDecimal(text)
+	case TypeBool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		value.text = strconv.FormatBool(b)
+	case TypeACID:
+		var id ACID
+		id, err = ParseACID(text)
+		value.text = id.String()
+	case TypePath:
+		if text == "" {
+			err = errors.New("path is empty")
+		}
+		value.text = path.Clean(text)
+	default:
+		err = fmt.Errorf("%w: %d", ErrUnknownValueType, t)
+	}
+
+	if err != nil {
+		return Value{}, fmt.Errorf("%w: %q is not a valid %s: %w", ErrValueType, text, t, err)
+	}
+
+	return
+}
+
+// Guess the type of text. Only types which keep text exactly as written are
+// considered, falling back to a string.
+func InferValue(text string) Value {
+	for _, t := range inferredValueTypes {
+		if t == TypePath && !strings.HasPrefix(text, "/") && !strings.HasPrefix(text, "~/") {
+			continue
+		}
+
+		value, err := NewValue(t, text)
+		if err == nil && value.text == text {
+			return value
+		}
+	}
+
+	return StringValue(text)
+}
+
+func StringValue(s string) Value {
+	return Value{Type: TypeString, text: s}
+}
+
+func DateValue(date time.Time) Value {
+	return Value{Type: TypeDate, text: date.Format(DateLayout)}
+}
+
+func DateTimeValue(dateTime time.Time) Value {
+	return Value{Type: TypeDateTime, text: dateTime.Format(time.RFC3339)}
+}
+
+func IntegerValue(n int64) Value {
+	return Value{Type: TypeInteger, text: strconv.FormatInt(n, 10)}
+}
+
+// Makes a decimal value, rounded to the given number of decimal places.
+func DecimalValue(x *big.Rat, places int) Value {
+	return Value{Type: TypeDecimal, text: x.FloatString(places)}
+}
+
+func BoolValue(b bool) Value {
+	return Value{Type: TypeBool, text: strconv.FormatBool(b)}
+}
+
+func ACIDValue(id ACID) Value {
+	return Value{Type: TypeACID, text: id.String()}
+}
+
+func PathValue(p string) Value {
+	return Value{Type: TypePath, text: path.Clean(p)}
+}
+
+// Returns the value's canonical text form.
+func (value Value) String() string {
+	return value.text
+}
+
+// Returns the value of a date or datetime.
+func (value Value) Time() (time.Time, error) {
+	switch value.Type {
+	case TypeDate:
+		return time.Parse(DateLayout, value.text)
+	case TypeDateTime:
+		return time.Parse(time.RFC3339, value.text)
+	}
+
+	return time.Time{}, value.typeError("date")
+}
+
+func (value Value) Int() (int64, error) {
+	if value.Type != TypeInteger {
+		return 0, value.typeError(TypeInteger.String())
+	}
+
+	return strconv.ParseInt(value.text, 10, 64)
+}
+
+// Returns the value of a decimal or integer.
+func (value Value) Decimal() (*big.Rat, error) {
+	if value.Type != TypeDecimal && value.Type != TypeInteger {
+		return nil, value.typeError(TypeDecimal.String())
+	}
+
+	x, ok := new(big.Rat).SetString(value.text)
+	if !ok {
+		return nil, value.typeError(TypeDecimal.String())
+	}
+
+	return x, nil
+}
+
+func (value Value) Bool() (bool, error) {
+	if value.Type != TypeBool {
+		return false, value.typeError(TypeBool.String())
+	}
+
+	return strconv.ParseBool(value.text)
+}
+
+func (value Value) ACID() (ACID, error) {
+	if value.Type != TypeACID {
+		return ACID{}, value.typeError(TypeACID.String())
+	}
+
+	return ParseACID(value.text)
+}
+
+func (value Value) Path() (string, error) {
+	if value.Type != TypePath {
+		return "", value.typeError(TypePath.String())
+	}
+
+	return value.text, nil
+}
+
+func (value Value) typeError(expected string) error {
+	return fmt.Errorf("%w: %q is a %s, not a %s", ErrValueType, value.text, value.Type, expected)
+}
+
+// Compare two values, returning -1, 0 or 1.
+//
+// Numbers compare numerically and dates chronologically. Anything else, or
+// values of unrelated types, compare by their text.
+func CompareValues(a, b Value) int {
+	if x, err := a.Decimal(); err == nil {
+		if y, err := b.Decimal(); err == nil {
+			return x.Cmp(y)
+		}
+	}
+
+	if x, err := a.Time(); err == nil {
+		if y, err := b.Time(); err == nil {
+			return x.Compare(y)
+		}
+	}
+
+	return cmp.Compare(a.text, b.text)
+}
+
+func canonicalDecimal(text string) (string, error) {
+	matches := decimalRegex.FindStringSubmatch(text)
+	if matches == nil {
+		return "", errors.New("expected digits with an optional decimal point")
+	}
+
+	x, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return "", errors.New("expected digits with an optional decimal point")
+	}
+
+	return x.FloatString(len(matches[2])), nil
+}
+
+// Get an entry's metadata value.
+func (entry *Entry) Value(key string) (Value, error) {
+	value, ok := entry.Metadata[key]
+	if !ok {
+		return Value{}, fmt.Errorf("%w: %q", ErrMetadataNotFound, key)
+	}
+
+	return value, nil
+}
+
+// Get an entry's metadata value as text, whatever its type.
+func (entry *Entry) Text(key string) (string, error) {
+	value, err := entry.Value(key)
+	return value.String(), err
+}
+
+func (entry *Entry) Time(key string) (time.Time, error) {
+	value, err := entry.Value(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return value.Time()
+}
+
+func (entry *Entry) Int(key string) (int64, error) {
+	value, err := entry.Value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return value.Int()
+}
+
+func (entry *Entry) Decimal(key string) (*big.Rat, error) {
+	value, err := entry.Value(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.Decimal()
+}
+
+func (entry *Entry) Bool(key string) (bool, error) {
+	value, err := entry.Value(key)
+	if err != nil {
+		return false, err
+	}
+
+	return value.Bool()
+}
+
+func (entry *Entry) ACIDRef(key string) (ACID, error) {
+	value, err := entry.Value(key)
+	if err != nil {
+		return ACID{}, err
+	}
+
+	return value.ACID()
+}
+
+func (entry *Entry) Path(key string) (string, error) {
+	value, err := entry.Value(key)
+	if err != nil {
+		return "", err
+	}
+
+	return value.Path()
+}
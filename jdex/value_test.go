@@ -0,0 +1,108 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferValue_TestCases(t *testing.T) {
+	testCases := map[string]jdex.ValueType{
+		"hello":                jdex.TypeString,
+		"true":                 jdex.TypeBool,
+		"42":                   jdex.TypeInteger,
+		"007":                  jdex.TypeString,
+		"12.50":                jdex.TypeDecimal,
+		"2025-06-30":           jdex.TypeDate,
+		"2025-06-30T10:00:00Z": jdex.TypeDateTime,
+		"/home/razza":          jdex.TypePath,
+		"11.02":                jdex.TypeDecimal,
+	}
+
+	for text, expected := range testCases {
+		t.Run(text, func(t *testing.T) {
+			value := jdex.InferValue(text)
+			assert.Equal(t, expected, value.Type)
+			assert.Equal(t, text, value.String())
+		})
+	}
+}
+
+func TestNewValue_Canonical(t *testing.T) {
+	value, err := jdex.NewValue(jdex.TypeDecimal, "+0012.50")
+	assert.NoError(t, err)
+	assert.Equal(t, "12.50", value.String())
+
+	value, err = jdex.NewValue(jdex.TypeBool, "TRUE")
+	assert.NoError(t, err)
+	assert.Equal(t, "true", value.String())
+}
+
+func TestNewValue_Invalid(t *testing.T) {
+	_, err := jdex.NewValue(jdex.TypeDate, "next tuesday")
+	assert.ErrorIs(t, err, jdex.ErrValueType)
+
+	_, err = jdex.NewValue(jdex.TypeACID, "11")
+	assert.ErrorIs(t, err, jdex.ErrValueType)
+}
+
+func TestCompareValues_Numeric(t *testing.T) {
+	small, _ := jdex.NewValue(jdex.TypeDecimal, "9.5")
+	large := jdex.IntegerValue(10)
+
+	assert.Equal(t, -1, jdex.CompareValues(small, large))
+	assert.Equal(t, 1, jdex.CompareValues(large, small))
+}
+
+func Test_Entry_TypedAccessors(t *testing.T) {
+	due := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	entry := jdex.Entry{
+		Metadata: map[string]jdex.Value{
+			"Amount":    jdex.DecimalValue(big.NewRat(2501, 2), 2),
+			"Due":       jdex.DateValue(due),
+			"Paid":      jdex.BoolValue(false),
+			"Paid from": jdex.ACIDValue(jdex.MustParseACID("11.02")),
+		},
+	}
+
+	amount, err := entry.Decimal("Amount")
+	assert.NoError(t, err)
+	assert.Equal(t, "1250.50", amount.FloatString(2))
+
+	actualDue, err := entry.Time("Due")
+	assert.NoError(t, err)
+	assert.Equal(t, due, actualDue)
+
+	paid, err := entry.Bool("Paid")
+	assert.NoError(t, err)
+	assert.False(t, paid)
+
+	paidFrom, err := entry.ACIDRef("Paid from")
+	assert.NoError(t, err)
+	assert.Equal(t, jdex.MustParseACID("11.02"), paidFrom)
+
+	_, err = entry.Int("Due")
+	assert.ErrorIs(t, err, jdex.ErrValueType)
+
+	_, err = entry.Text("Vendor")
+	assert.ErrorIs(t, err, jdex.ErrMetadataNotFound)
+}
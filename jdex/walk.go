@@ -0,0 +1,186 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdex
+
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Get what's directly below id, in order: the areas for the zero ID, the
+// categories of an area, the entries of a category, or the sub-entries of an
+// entry. Nothing is yielded if id doesn't exist.
+func (index *Index) Children(id ACID) iter.Seq[ACID] {
+	return func(yield func(ACID) bool) {
+		index.mu.RLock()
+		ids := index.data.children(id)
+		index.mu.RUnlock()
+
+		for _, child := range ids {
+			if !yield(child) {
+				return
+			}
+		}
+	}
+}
+
+func (data *indexData) children(id ACID) (ids []ACID) {
+	switch {
+	case id == ACID{}:
+		for _, k := range slices.Sorted(maps.Keys(data.areas)) {
+			ids = append(ids, ACID{Area: k})
+		}
+	case id.Level() == LevelArea:
+		area, ok := data.areas[id.Area]
+		if !ok {
+			return
+		}
+
+		for k := range area.categories {
+			ids = append(ids, ACID{Area: id.Area, Category: k})
+		}
+	case id.Level() == LevelCategory:
+		category, ok := data.areas[id.Area].categories[id.Category]
+		if !ok {
+			return
+		}
+
+		ids = data.entryKeyIDs(category.entries)
+	case id.Level() == LevelEntry:
+		if _, ok := data.entries[id.String()]; !ok {
+			return
+		}
+
+		ids = data.entryKeyIDs(data.subs[id.String()])
+	}
+
+	slices.SortFunc(ids, CompareACIDs)
+	return
+}
+
+func (data *indexData) entryKeyIDs(keys map[string]bool) []ACID {
+	ids := make([]ACID, 0, len(keys))
+	for k := range keys {
+		ids = append(ids, data.entries[k].ID)
+	}
+
+	return ids
+}
+
+// Walk everything below id depth-first, in order, along with its level. The
+// zero ID walks the whole index.
+//
+// The walk goes over a snapshot, so the index can be changed along the way.
+func (index *Index) Walk(id ACID) iter.Seq2[ACID, Level] {
+	return func(yield func(ACID, Level) bool) {
+		index.Snapshot().data.walkFrom(id, yield)
+	}
+}
+
+func (data *indexData) walk(id ACID) iter.Seq2[ACID, Level] {
+	return func(yield func(ACID, Level) bool) {
+		data.walkFrom(id, yield)
+	}
+}
+
+func (data *indexData) walkFrom(id ACID, yield func(ACID, Level) bool) bool {
+	for _, child := range data.children(id) {
+		if !yield(child, child.Level()) || !data.walkFrom(child, yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Get every entry and sub-entry, in order.
+func (index *Index) AllEntries() iter.Seq2[ACID, Entry] {
+	return index.EntriesIn(nil)
+}
+
+// Get the entries and sub-entries a selector picks out, in order. A nil
+// selector picks out every entry.
+//
+// Like Walk, this goes over a snapshot.
+func (index *Index) EntriesIn(selector ACIDSelector) iter.Seq2[ACID, Entry] {
+	return func(yield func(ACID, Entry) bool) {
+		for id, entry := range index.Snapshot().data.entriesIn(selector) {
+			if !yield(id, entry.clone()) {
+				return
+			}
+		}
+	}
+}
+
+func (data *indexData) entriesIn(selector ACIDSelector) iter.Seq2[ACID, Entry] {
+	return func(yield func(ACID, Entry) bool) {
+		for id, level := range data.walk(ACID{}) {
+			if level != LevelEntry && level != LevelSub {
+				continue
+			}
+
+			if selector != nil && !selector.Match(id) {
+				continue
+			}
+
+			if !yield(id, data.entries[id.String()]) {
+				return
+			}
+		}
+	}
+}
+
+// Keep only the values keep returns true for.
+func Filter[V any](seq iter.Seq[V], keep func(V) bool) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for v := range seq {
+			if keep(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Keep only the pairs keep returns true for.
+func Filter2[K, V any](seq iter.Seq2[K, V], keep func(K, V) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if keep(k, v) && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Keep only the IDs at one of the levels, such as the categories of a walk.
+func AtLevel(seq iter.Seq2[ACID, Level], levels ...Level) iter.Seq[ACID] {
+	return func(yield func(ACID) bool) {
+		for id, level := range seq {
+			if slices.Contains(levels, level) && !yield(id) {
+				return
+			}
+		}
+	}
+}
+
+// Keep only the entries with a tag.
+func WithTag(seq iter.Seq2[ACID, Entry], tag string) iter.Seq2[ACID, Entry] {
+	return Filter2(seq, func(_ ACID, entry Entry) bool {
+		return entry.HasTag(tag)
+	})
+}
@@ -0,0 +1,117 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package jdex_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWalkIndex() *jdex.Index {
+	index, _ := jdex.NewIndex()
+	for _, input := range []string{"12.01", "11.02", "11.01", "21.01"} {
+		id := jdex.MustParseACID(input)
+		index.PutArea(id, "")
+		index.PutCategory(id, "")
+		index.PutEntry(jdex.Entry{ID: id, Name: input})
+	}
+
+	index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.01+002"), Tags: []string{"tax"}})
+	index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.01+001")})
+	return index
+}
+
+func Test_Index_Walk(t *testing.T) {
+	index := newWalkIndex()
+
+	var walked []string
+	for id, level := range index.Walk(jdex.ACID{}) {
+		walked = append(walked, level.String()+" "+id.String())
+	}
+
+	assert.Equal(t, []string{
+		"area 00-09", "category 00", "entry 00.00",
+		"area 10-19",
+		"category 11", "entry 11.01", "sub-entry 11.01+001", "sub-entry 11.01+002", "entry 11.02",
+		"category 12", "entry 12.01",
+		"area 20-29", "category 21", "entry 21.01",
+	}, walked)
+
+	walked = nil
+	for id := range index.Walk(jdex.ACID{Area: '1', Category: "1"}) {
+		walked = append(walked, id.String())
+	}
+
+	assert.Equal(t, []string{"11.01", "11.01+001", "11.01+002", "11.02"}, walked)
+
+	walked = nil
+	for id := range index.Walk(jdex.ACID{}) {
+		walked = append(walked, id.String())
+		if len(walked) == 3 {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"00-09", "00", "00.00"}, walked)
+}
+
+func Test_Index_Children(t *testing.T) {
+	index := newWalkIndex()
+
+	ids := func(id jdex.ACID) (strs []string) {
+		for child := range index.Children(id) {
+			strs = append(strs, child.String())
+		}
+
+		return
+	}
+
+	assert.Equal(t, []string{"00-09", "10-19", "20-29"}, ids(jdex.ACID{}))
+	assert.Equal(t, []string{"11", "12"}, ids(jdex.ACID{Area: '1'}))
+	assert.Equal(t, []string{"11.01", "11.02"}, ids(jdex.ACID{Area: '1', Category: "1"}))
+	assert.Equal(t, []string{"11.01+001", "11.01+002"}, ids(jdex.MustParseACID("11.01")))
+	assert.Empty(t, ids(jdex.ACID{Area: '5'}))
+	assert.Empty(t, ids(jdex.MustParseACID("11.09")))
+}
+
+func Test_Index_EntriesIn(t *testing.T) {
+	index := newWalkIndex()
+	selector, _ := jdex.ParseACIDSelector("11.01-11.02")
+
+	var ids []string
+	for id, entry := range index.EntriesIn(selector) {
+		assert.Equal(t, id, entry.ID)
+		ids = append(ids, id.String())
+	}
+
+	assert.Equal(t, []string{"11.01", "11.01+001", "11.01+002", "11.02"}, ids)
+
+	assert.Len(t, slices.Collect(jdex.AtLevel(index.Walk(jdex.ACID{}), jdex.LevelCategory)), 4)
+	assert.Equal(t, []jdex.ACID{jdex.MustParseACID("12.01"), jdex.MustParseACID("21.01")}, slices.Collect(jdex.Filter(
+		jdex.AtLevel(index.Walk(jdex.ACID{}), jdex.LevelEntry),
+		func(id jdex.ACID) bool { return id.Area != '0' && id.CategoryString() != "11" },
+	)))
+
+	ids = nil
+	for id := range jdex.WithTag(index.AllEntries(), "TAX") {
+		ids = append(ids, id.String())
+	}
+
+	assert.Equal(t, []string{"11.01+002"}, ids)
+}
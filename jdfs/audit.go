@@ -0,0 +1,209 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// A single change made to a store, as kept in its audit log.
+type AuditRecord struct {
+	Time    time.Time    `json:"time"`
+	User    string       `json:"user,omitempty"`    // Who made the change.
+	Host    string       `json:"host,omitempty"`    // Machine the change was made from.
+	Command string       `json:"command,omitempty"` // Command line which made the change.
+	Change  string       `json:"change"`            // What happened, such as `renamed` or `move`.
+	ID      string       `json:"id,omitempty"`      // Area, category or entry changed, if known.
+	Before  *AuditValues `json:"before,omitempty"`
+	After   *AuditValues `json:"after,omitempty"`
+}
+
+// What an area, category, entry or directory was like on either side of a
+// change.
+type AuditValues struct {
+	Name     string            `json:"name,omitempty"`
+	Path     string            `json:"path,omitempty"` // Relative to the store.
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+const AuditLogFilename = "Audit Log.jsonl"
+
+// Who's making changes, written along with each one.
+type auditActor struct {
+	user    string
+	host    string
+	command string
+}
+
+// Get the path to the audit log.
+func (store *Store) AuditLogPath() (string, error) {
+	return store.SystemFilePath(AuditLogFilename)
+}
+
+// Read every record from the audit log, oldest first.
+func (store *Store) AuditLog() (records []AuditRecord, err error) {
+	logPath, err := store.AuditLogPath()
+	if err != nil {
+		return
+	}
+
+	logFile, err := os.Open(logPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return
+	}
+	defer logFile.Close()
+
+	scanner := bufio.NewScanner(logFile)
+	scanner.Buffer(nil, 1<<24)
+	for scanner.Scan() {
+		var record AuditRecord
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			// a line cut short by a crash
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
+
+// Start writing the changes made through the store to its audit log.
+func (store *Store) startAudit() {
+	store.actor = auditActor{command: strings.Join(append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...), " ")}
+	if current, err := user.Current(); err == nil {
+		store.actor.user = current.Username
+	}
+	store.actor.host, _ = os.Hostname()
+
+	store.Observe(func(event StoreEvent) {
+		record := AuditRecord{Change: event.Kind.String(), ID: event.ID.String()}
+
+		switch event.Kind {
+		case jdex.EventAdded:
+			record.After = &AuditValues{Name: event.Name, Path: store.relPath(event.Path)}
+		case jdex.EventRenamed:
+			record.Before = &AuditValues{Name: event.OldName, Path: store.relPath(event.OldPath)}
+			record.After = &AuditValues{Name: event.Name, Path: store.relPath(event.Path)}
+		case jdex.EventMetadataChanged:
+			record.Before = &AuditValues{Tags: event.OldTags, Metadata: auditMetadata(event.OldMetadata)}
+			record.After = &AuditValues{Name: event.Name, Tags: event.Tags, Metadata: auditMetadata(event.Metadata)}
+		case jdex.EventRemoved:
+			record.Before = &AuditValues{Name: event.Name, Path: store.relPath(event.OldPath)}
+		}
+
+		store.writeAudit(record)
+	})
+}
+
+// Write a directory made, moved or removed to the audit log.
+func (store *Store) auditStep(step planStep) {
+	record := AuditRecord{Change: step.Op}
+
+	switch step.Op {
+	case planStepMove:
+		record.Before = &AuditValues{Path: store.relPath(step.From)}
+		record.After = &AuditValues{Path: store.relPath(step.To)}
+	case planStepMkdir:
+		record.After = &AuditValues{Path: store.relPath(step.Path)}
+	case planStepRmdir:
+		record.Before = &AuditValues{Path: store.relPath(step.Path)}
+	}
+
+	for _, p := range []string{step.To, step.Path, step.From} {
+		if id, err := store.Locate(p); p != "" && err == nil {
+			record.ID = id.String()
+			break
+		}
+	}
+
+	store.writeAudit(record)
+}
+
+// Append a record to the audit log. The log is kept on a best effort basis,
+// so failing to write it doesn't stop the change being made, but it is
+// reported.
+func (store *Store) writeAudit(record AuditRecord) {
+	record.Time = time.Now()
+	record.User = store.actor.user
+	record.Host = store.actor.host
+	record.Command = store.actor.command
+
+	if err := store.appendAudit(record); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to write the audit log: %v\n", err)
+	}
+}
+
+func (store *Store) appendAudit(record AuditRecord) error {
+	logPath, err := store.AuditLogPath()
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = logFile.Write(append(line, '\n'))
+	return errors.Join(err, logFile.Close())
+}
+
+// Make a path relative to the store, leaving it as it is if it's outside.
+func (store *Store) relPath(p string) string {
+	if p == "" {
+		return p
+	}
+
+	rel, err := filepath.Rel(store.Root, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return p
+	}
+
+	return rel
+}
+
+func auditMetadata(metadata map[string]jdex.Value) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		values[key] = value.String()
+	}
+
+	return values
+}
@@ -0,0 +1,113 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Store_AuditLog(t *testing.T) {
+	store := newUndoStore(t)
+	id := jdex.MustParseACID("11.01")
+
+	logPath, err := store.AuditLogPath()
+	assert.NoError(t, err)
+	assert.FileExists(t, logPath)
+	assert.NoError(t, os.Truncate(logPath, 0))
+
+	assert.NoError(t, store.PutEntry(jdex.Entry{
+		ID:       id,
+		Name:     "Acme Ltd",
+		Tags:     []string{"client"},
+		Metadata: map[string]jdex.Value{"Contact": jdex.StringValue("Wile")},
+	}))
+
+	records, err := store.AuditLog()
+	assert.NoError(t, err)
+
+	var changes []string
+	for _, record := range records {
+		changes = append(changes, record.Change+" "+record.ID)
+		assert.NotEmpty(t, record.Command)
+		assert.False(t, record.Time.IsZero())
+	}
+
+	// directories are moved before the index changes
+	if !assert.Equal(t, []string{"move 11.01", "renamed 11.01", "metadata changed 11.01"}, changes) {
+		t.FailNow()
+	}
+
+	renamed := records[1]
+	assert.Equal(t, "Acme", renamed.Before.Name)
+	assert.Equal(t, filepath.Join("10-19 Finance", "11 Clients", "11.01 Acme"), renamed.Before.Path)
+	assert.Equal(t, "Acme Ltd", renamed.After.Name)
+
+	changed := records[2]
+	assert.Empty(t, changed.Before.Tags)
+	assert.Equal(t, []string{"client"}, changed.After.Tags)
+	assert.Equal(t, map[string]string{"Contact": "Wile"}, changed.After.Metadata)
+
+	moved := records[0]
+	assert.Equal(t, filepath.Join("10-19 Finance", "11 Clients", "11.01 Acme"), moved.Before.Path)
+	assert.Equal(t, filepath.Join("10-19 Finance", "11 Clients", "11.01 Acme Ltd"), moved.After.Path)
+
+	// undoing is logged too
+	assert.NoError(t, os.Truncate(logPath, 0))
+
+	err = store.Record("rm 11.01+001", func() error {
+		_, err := store.Index.RemoveEntry(jdex.MustParseACID("11.01+001"))
+		if err != nil {
+			return err
+		}
+
+		return store.Save()
+	})
+	assert.NoError(t, err)
+
+	_, err = store.Undo(1)
+	assert.NoError(t, err)
+
+	records, err = store.AuditLog()
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "removed", records[0].Change)
+		assert.Equal(t, "added", records[1].Change)
+		assert.Equal(t, "11.01+001", records[1].ID)
+	}
+}
+
+func Test_NewStore_AuditLog(t *testing.T) {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.NoError(t, store.Index.PutArea(jdex.MustParseACID("11.01"), "Finance"))
+
+	records, err := store.AuditLog()
+	assert.NoError(t, err)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "added", records[0].Change)
+		assert.Equal(t, "10-19", records[0].ID)
+	}
+}
@@ -0,0 +1,74 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// A change made to a store's index, along with where it is in the store.
+type StoreEvent struct {
+	jdex.Event
+	Store   *Store
+	Path    string // Directory it's in now, empty once it's removed.
+	OldPath string // Directory it was in before being renamed or removed.
+}
+
+// Call observer with every change made to the store's index from now on,
+// returning a function which stops it.
+//
+// Paths are where the index put things when the change was made. The store's
+// own methods, such as PutEntry, create or move the directories to match
+// after the index changes.
+func (store *Store) Observe(observer func(StoreEvent)) (stop func()) {
+	return store.Index.Observe(func(event jdex.Event) {
+		storeEvent := StoreEvent{Event: event, Store: store}
+
+		parentPath := store.Root
+		parentIDs := []jdex.ACID{event.ID.AreaID(), event.ID.CategoryID(), event.ID.EntryID()}
+		for i, name := range event.Parents {
+			parentPath = childPath(parentPath, parentIDs[i], name)
+		}
+
+		switch event.Kind {
+		case jdex.EventRemoved:
+			storeEvent.OldPath = childPath(parentPath, event.ID, event.Name)
+		case jdex.EventRenamed:
+			storeEvent.OldPath = childPath(parentPath, event.ID, event.OldName)
+			fallthrough
+		default:
+			storeEvent.Path = childPath(parentPath, event.ID, event.Name)
+		}
+
+		observer(storeEvent)
+	})
+}
+
+// Get the path an area, category or entry would have with a given name.
+func childPath(parentPath string, id jdex.ACID, name string) string {
+	switch id.Level() {
+	case jdex.LevelArea:
+		return path.Join(parentPath, fmt.Sprintf("%s %s", id.AreaString(), name))
+	case jdex.LevelCategory:
+		return path.Join(parentPath, fmt.Sprintf("%s %s", id.CategoryString(), TransformFilename(name)))
+	}
+
+	return path.Join(parentPath, EntryFilename(jdex.Entry{ID: id, Name: name}))
+}
@@ -0,0 +1,95 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Store_Observe(t *testing.T) {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	id := jdex.MustParseACID("11.01")
+	store.Index.PutArea(id, "Finance")
+	store.Index.PutCategory(id, "Clients")
+	assert.NoError(t, store.PutEntry(jdex.Entry{ID: id, Name: "Acme"}))
+	assert.NoError(t, store.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.01+001"), Name: "Invoice"}))
+
+	var events []jdfs.StoreEvent
+	stop := store.Observe(func(event jdfs.StoreEvent) {
+		events = append(events, event)
+	})
+	defer stop()
+
+	category := filepath.Join(store.Root, "10-19 Finance", "11 Clients")
+
+	assert.NoError(t, store.PutEntry(jdex.Entry{ID: id, Name: "Acme Ltd"}))
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, jdex.EventRenamed, events[0].Kind)
+		assert.Same(t, store, events[0].Store)
+		assert.Equal(t, filepath.Join(category, "11.01 Acme Ltd"), events[0].Path)
+		assert.Equal(t, filepath.Join(category, "11.01 Acme"), events[0].OldPath)
+	}
+
+	events = nil
+	_, err = store.Index.RemoveEntry(id)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, jdex.EventRemoved, events[1].Kind)
+		assert.Empty(t, events[1].Path)
+		assert.Equal(t, filepath.Join(category, "11.01 Acme Ltd", "11.01+001 Invoice"), events[1].OldPath)
+	}
+}
+
+func Test_Store_Observe_PathsAtChange(t *testing.T) {
+	store := newUndoStore(t)
+	id := jdex.MustParseACID("11.01")
+
+	var events []jdfs.StoreEvent
+	stop := store.Observe(func(event jdfs.StoreEvent) {
+		events = append(events, event)
+	})
+	defer stop()
+
+	// removing the entry and renaming its area in one change
+	replacement := store.Index.Snapshot()
+	replacement.PutArea(id, "Money")
+	replacement.RemoveEntry(id)
+	store.Index.Replace(replacement)
+
+	var kinds []jdex.EventKind
+	for _, event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+	if !assert.Equal(t, []jdex.EventKind{jdex.EventRemoved, jdex.EventRemoved, jdex.EventRenamed}, kinds) {
+		t.FailNow()
+	}
+
+	entry := filepath.Join(store.Root, "10-19 Finance", "11 Clients", "11.01 Acme")
+	assert.Equal(t, entry, events[0].OldPath)
+	assert.Equal(t, filepath.Join(entry, "11.01+001 Invoice"), events[1].OldPath)
+	assert.Equal(t, filepath.Join(store.Root, "10-19 Finance"), events[2].OldPath)
+	assert.Equal(t, filepath.Join(store.Root, "10-19 Money"), events[2].Path)
+}
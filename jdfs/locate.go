@@ -0,0 +1,117 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+var ErrOutsideStore = errors.New("path is outside the store")
+var ErrNoLocation = errors.New("path is not in an area")
+
+// Get the area, category, entry or sub-entry a path is in, by parsing the
+// directory names leading to it against the index. Paths below an entry's
+// directory are located at that entry.
+func (store *Store) Locate(p string) (id jdex.ACID, err error) {
+	root, err := absPath(store.Root)
+	if err != nil {
+		return
+	}
+
+	p, err = absPath(p)
+	if err != nil {
+		return
+	}
+
+	rel, err := filepath.Rel(root, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		err = fmt.Errorf("%w: %q", ErrOutsideStore, p)
+		return
+	}
+
+	if rel == "." {
+		err = fmt.Errorf("%w: %q", ErrNoLocation, p)
+		return
+	}
+
+	levels := []jdex.Level{jdex.LevelArea, jdex.LevelCategory, jdex.LevelEntry, jdex.LevelSub}
+	for n, name := range strings.Split(rel, string(filepath.Separator)) {
+		if n >= len(levels) {
+			break
+		}
+
+		child, ok := store.locateChild(id, levels[n], name)
+		if !ok {
+			break
+		}
+
+		id = child
+	}
+
+	if id.Area == 0 {
+		err = fmt.Errorf("%w: %q", ErrNoLocation, p)
+	}
+
+	return
+}
+
+// Parse a directory name, such as `11.03 Savings`, as a child of parent.
+func (store *Store) locateChild(parent jdex.ACID, level jdex.Level, name string) (id jdex.ACID, ok bool) {
+	prefix, _, _ := strings.Cut(name, " ")
+
+	id, parsedLevel, err := jdex.ParseID(prefix)
+	if err != nil || parsedLevel != level || id.System != "" {
+		return
+	}
+
+	switch level {
+	case jdex.LevelCategory:
+		ok = id.Area == parent.Area
+	case jdex.LevelEntry:
+		ok = id.Area == parent.Area && id.Category == parent.Category
+	case jdex.LevelSub:
+		ok = id.EntryID() == parent
+	default:
+		ok = true
+	}
+
+	if ok {
+		_, err = store.Index.Name(id)
+		ok = err == nil
+	}
+
+	return
+}
+
+// Make a path absolute, following symbolic links where it exists.
+func absPath(p string) (string, error) {
+	p, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved, nil
+	}
+
+	return p, nil
+}
@@ -0,0 +1,80 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Store_Locate(t *testing.T) {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	id := jdex.MustParseACID("11.03")
+	store.Index.PutArea(id, "Finance")
+	store.Index.PutCategory(id, "Banking")
+	store.PutEntry(jdex.Entry{ID: id, Name: "Savings"})
+	store.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.03+001"), Name: "Statement"})
+
+	cases := map[string]string{
+		"10-19 Finance":                                                  "10-19",
+		"10-19 Finance/11 Banking":                                       "11",
+		"10-19 Finance/11 Banking/11.03 Savings":                         "11.03",
+		"10-19 Finance/11 Banking/11.03 Savings/Index.txt":               "11.03",
+		"10-19 Finance/11 Banking/11.03 Savings/11.03+001 Statement/a/b": "11.03+001",
+		"10-19 Finance/11 Banking/Loose files":                           "11",
+		"10-19 Finance/11 Banking/11.04 Not in the index":                "11",
+	}
+
+	for rel, expected := range cases {
+		located, err := store.Locate(filepath.Join(store.Root, rel))
+		if assert.NoError(t, err, rel) {
+			assert.Equal(t, expected, located.String(), rel)
+		}
+	}
+
+	_, err = store.Locate(store.Root)
+	assert.ErrorIs(t, err, jdfs.ErrNoLocation)
+
+	_, err = store.Locate(filepath.Dir(store.Root))
+	assert.ErrorIs(t, err, jdfs.ErrOutsideStore)
+}
+
+func Test_Store_Locate_Extended(t *testing.T) {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	// area A, category 12, entry 34, which could be taken for system A12
+	assert.NoError(t, store.Index.SetScheme(jdex.ExtendedScheme))
+	id := jdex.MustParseACID("A12.34")
+	store.Index.PutArea(id, "Archive")
+	assert.NoError(t, store.PutCategory(id, "Letters"))
+	assert.NoError(t, store.PutEntry(jdex.Entry{ID: id, Name: "Bank"}))
+
+	located, err := store.Locate(filepath.Join(store.Root, "A0-A9 Archive", "A12 Letters", "A12.34 Bank"))
+	assert.NoError(t, err)
+	assert.Equal(t, id, located)
+}
@@ -0,0 +1,115 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Marker is kept at the root of every store, so it can be told apart from
+// other directories and found from anywhere inside it.
+type Marker struct {
+	ID     string `json:"id"`     // UUID of the store, which stays the same if it's moved.
+	Format int    `json:"format"` // Layout version of the store.
+}
+
+// Name of the marker file at the root of a store.
+const MarkerFilename = ".rzjd"
+
+// Layout version of stores made by this version of rzjd.
+const StoreFormat = 1
+
+var ErrStoreNotFound = errors.New("no store found in this directory or its parents")
+var ErrStoreFormat = errors.New("store was made by a newer version of rzjd")
+
+// Make a marker for a new store.
+func NewMarker() (marker Marker, err error) {
+	var uuid [16]byte
+	_, err = rand.Read(uuid[:])
+	if err != nil {
+		return
+	}
+
+	// version 4, variant 1
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	marker.ID = fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+	marker.Format = StoreFormat
+	return
+}
+
+// Read the marker at the root of a store.
+func ReadMarker(root string) (marker Marker, err error) {
+	data, err := os.ReadFile(path.Join(root, MarkerFilename))
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &marker)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", MarkerFilename, err)
+		return
+	}
+
+	if marker.Format > StoreFormat {
+		err = fmt.Errorf("%w: format %d", ErrStoreFormat, marker.Format)
+	}
+
+	return
+}
+
+// Write the marker at the root of a store.
+func WriteMarker(root string, marker Marker) error {
+	data, err := json.MarshalIndent(marker, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path.Join(root, MarkerFilename), append(data, '\n'), 0644)
+}
+
+// Find the root of the store dir is in, by looking for its marker in dir and
+// then each of its parents, the way git finds `.git`.
+func FindStore(dir string) (root string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+
+	for {
+		info, statErr := os.Stat(filepath.Join(dir, MarkerFilename))
+		if statErr == nil && !info.IsDir() {
+			return dir, nil
+		} else if statErr != nil && !errors.Is(statErr, os.ErrNotExist) {
+			return "", statErr
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrStoreNotFound
+		}
+
+		dir = parent
+	}
+}
@@ -0,0 +1,82 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewStore_WritesMarker(t *testing.T) {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	marker, err := jdfs.ReadMarker(store.Root)
+	assert.NoError(t, err)
+	assert.Equal(t, store.Marker, marker)
+	assert.Equal(t, jdfs.StoreFormat, marker.Format)
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, marker.ID)
+}
+
+func Test_OpenStore_AddsMissingMarker(t *testing.T) {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	os.Remove(filepath.Join(store.Root, jdfs.MarkerFilename))
+
+	reopened, err := jdfs.OpenStore(store.Root)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, reopened.Marker.ID)
+	assert.FileExists(t, filepath.Join(store.Root, jdfs.MarkerFilename))
+}
+
+func Test_OpenStore_FailNewerFormat(t *testing.T) {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	jdfs.WriteMarker(store.Root, jdfs.Marker{ID: store.Marker.ID, Format: jdfs.StoreFormat + 1})
+
+	_, err = jdfs.OpenStore(store.Root)
+	assert.ErrorIs(t, err, jdfs.ErrStoreFormat)
+}
+
+func Test_FindStore(t *testing.T) {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	nested := filepath.Join(store.Root, "10-19 Finance", "11 Banking")
+	os.MkdirAll(nested, 0755)
+
+	root, err := jdfs.FindStore(nested)
+	assert.NoError(t, err)
+	assert.Equal(t, store.Root, root)
+
+	_, err = jdfs.FindStore(t.TempDir())
+	assert.ErrorIs(t, err, jdfs.ErrStoreNotFound)
+}
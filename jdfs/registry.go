@@ -0,0 +1,167 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// Registry maps system codes, such as `W01`, to the stores they're kept in,
+// so `SYS.AC.ID` identifiers can be resolved. Stores are opened as they're
+// needed.
+type Registry struct {
+	Local   *Store            // Store IDs without a system code refer to.
+	Systems map[string]string // Store roots by system code.
+
+	stores map[string]*Store
+}
+
+var ErrUnknownSystem = errors.New("system is not registered")
+
+// Create a registry of the given systems, with local as the store IDs without
+// a system code refer to.
+func NewRegistry(local *Store, systems map[string]string) *Registry {
+	return &Registry{
+		Local:   local,
+		Systems: systems,
+		stores:  map[string]*Store{},
+	}
+}
+
+// Get the registered system codes, in order.
+func (registry *Registry) Codes() []string {
+	return slices.Sorted(maps.Keys(registry.Systems))
+}
+
+// Get the store a system is kept in. The empty code is the local store.
+func (registry *Registry) Store(code string) (store *Store, err error) {
+	if code == "" {
+		return registry.Local, nil
+	}
+
+	if store, ok := registry.stores[code]; ok {
+		return store, nil
+	}
+
+	root, ok := registry.Systems[code]
+	if !ok {
+		err = fmt.Errorf("%w: %q", ErrUnknownSystem, code)
+		return
+	}
+
+	store = registry.Local
+	if store == nil {
+		store, err = OpenStore(root)
+	} else if same, _ := samePath(root, store.Root); !same {
+		store, err = OpenStore(root)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("system %s: %w", code, err)
+		return
+	}
+
+	registry.stores[code] = store
+	return
+}
+
+// Get the store an ID belongs to, along with the ID local to that store.
+func (registry *Registry) Resolve(id jdex.ACID) (store *Store, local jdex.ACID, err error) {
+	store, err = registry.Store(id.System)
+	if err != nil {
+		return
+	}
+
+	local = id
+	local.System = ""
+	return
+}
+
+// Get the codes of the systems kept in the local store, in order.
+func (registry *Registry) LocalCodes() (codes []string) {
+	if registry.Local == nil {
+		return
+	}
+
+	for _, code := range registry.Codes() {
+		if same, _ := samePath(registry.Systems[code], registry.Local.Root); same {
+			codes = append(codes, code)
+		}
+	}
+
+	return
+}
+
+// Get the entries picked out by a selector in the local store and every
+// registered system, in order. IDs from registered systems have their system
+// code set. Systems kept in the local store are only listed once, locally.
+//
+// Selectors without a system code, such as `11.*`, pick out entries in every
+// system, going by their IDs within it.
+func (registry *Registry) Select(selector jdex.ACIDSelector) (ids []jdex.ACID, err error) {
+	if registry.Local != nil {
+		localCodes := registry.LocalCodes()
+		for id := range registry.Local.Index.AllEntries() {
+			if selector.Match(id) || slices.ContainsFunc(localCodes, func(code string) bool {
+				coded := id
+				coded.System = code
+				return selector.Match(coded)
+			}) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	for _, code := range registry.Codes() {
+		var store *Store
+		store, err = registry.Store(code)
+		if err != nil {
+			return
+		}
+
+		if store == registry.Local {
+			continue
+		}
+
+		for id := range store.Index.AllEntries() {
+			remote := id
+			remote.System = code
+			if selector.Match(id) || selector.Match(remote) {
+				ids = append(ids, remote)
+			}
+		}
+	}
+
+	return
+}
+
+// Get an entry from whichever store its ID belongs to.
+func (registry *Registry) Entry(id jdex.ACID) (entry jdex.Entry, err error) {
+	store, local, err := registry.Resolve(id)
+	if err != nil {
+		return
+	}
+
+	entry, err = store.Index.Entry(local)
+	entry.ID = id
+	return
+}
@@ -0,0 +1,111 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs_test
+
+import (
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeRegistryStore(t *testing.T, name string) *jdfs.Store {
+	store, err := jdfs.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := jdex.MustParseACID("15.14")
+	store.Index.PutArea(id, "Work")
+	store.Index.PutCategory(id, "Projects")
+	if err := store.PutEntry(jdex.Entry{ID: id, Name: name}); err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func Test_Registry_Resolve(t *testing.T) {
+	local := makeRegistryStore(t, "Personal")
+	work := makeRegistryStore(t, "Work")
+
+	registry := jdfs.NewRegistry(local, map[string]string{
+		"P01": local.Root,
+		"W01": work.Root,
+	})
+
+	store, id, err := registry.Resolve(jdex.MustParseACID("W01.15.14"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, work.Root, store.Root)
+	assert.Equal(t, jdex.MustParseACID("15.14"), id)
+
+	store, _, err = registry.Resolve(jdex.MustParseACID("P01.15.14"))
+	assert.NoError(t, err)
+	assert.Same(t, local, store)
+
+	entry, err := registry.Entry(jdex.MustParseACID("W01.15.14"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Work", entry.Name)
+
+	_, _, err = registry.Resolve(jdex.MustParseACID("X01.15.14"))
+	assert.ErrorIs(t, err, jdfs.ErrUnknownSystem)
+}
+
+func Test_Registry_Select(t *testing.T) {
+	local := makeRegistryStore(t, "Personal")
+	work := makeRegistryStore(t, "Work")
+
+	registry := jdfs.NewRegistry(local, map[string]string{
+		"P01": local.Root,
+		"W01": work.Root,
+	})
+
+	for _, input := range []string{"1*", "15", "15.*", "15.14", "15.10-15.20", "10-19"} {
+		selector, err := jdex.ParseACIDSelector(input)
+		if !assert.NoError(t, err, input) {
+			continue
+		}
+
+		ids, err := registry.Select(selector)
+		assert.NoError(t, err, input)
+		assert.Equal(t, []jdex.ACID{
+			jdex.MustParseACID("15.14"),
+			jdex.MustParseACID("W01.15.14"),
+		}, ids, input)
+	}
+
+	// a system code picks out just that system, whether it's the local store
+	// or not
+	ids, err := registry.Select(jdex.ACIDPattern("W01.15.*"))
+	assert.NoError(t, err)
+	assert.Equal(t, []jdex.ACID{jdex.MustParseACID("W01.15.14")}, ids)
+
+	assert.Equal(t, []string{"P01"}, registry.LocalCodes())
+	for _, selector := range []jdex.ACIDSelector{
+		jdex.ACIDPattern("P01.15.*"),
+		jdex.IDSelector(jdex.ACID{System: "P01", Area: '1', Category: "5"}),
+		jdex.IDSelector(jdex.ACID{System: "P01", Area: '1'}),
+	} {
+		ids, err = registry.Select(selector)
+		assert.NoError(t, err)
+		assert.Equal(t, []jdex.ACID{jdex.MustParseACID("15.14")}, ids, selector)
+	}
+}
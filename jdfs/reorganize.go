@@ -0,0 +1,581 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// Plan describes how an existing folder hierarchy maps onto a Johnny.Decimal
+// system.
+//
+// Only entries move folders around. Areas and categories remember the folder
+// they were proposed from, but it's left in place and removed once empty.
+type Plan struct {
+	Source   string     // Root of the hierarchy being reorganised.
+	Items    []PlanItem // Areas, categories and entries, in index order.
+	Unmapped []string   // Files which aren't part of any entry.
+}
+
+// A single area, category or entry in a plan.
+type PlanItem struct {
+	ID   jdex.ACID // ID the folder will be given.
+	Name string    // Name the folder will be given.
+	Path string    // Source folder, relative to Plan.Source.
+}
+
+// A step taken while applying a plan, as written to the rollback log.
+type planStep struct {
+	Op    string `json:"op"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+	Path  string `json:"path,omitempty"`
+	Index string `json:"index,omitempty"`
+}
+
+const (
+	planStepIndex = "index"
+	planStepMkdir = "mkdir"
+	planStepMove  = "move"
+	planStepRmdir = "rmdir"
+)
+
+var ErrPlanConflict = errors.New("plan conflicts with the store")
+var ErrPlanParse = errors.New("failed to parse plan")
+
+var planSourceRegex = regexp.MustCompile(`^Source:\s*(.+)$`)
+var planItemRegex = regexp.MustCompile(`^(\S+)\s+(.+?)(?:\s+<-\s+(".*"))?$`)
+var planAreaRegex = regexp.MustCompile(`^([A-Z0-9])0-([A-Z0-9])9$`)
+var planCategoryRegex = regexp.MustCompile(`^[A-Z0-9]+$`)
+
+type planFolder struct {
+	name     string
+	path     string
+	size     int64
+	files    []string
+	children []*planFolder
+}
+
+// Propose a plan for the folder hierarchy at source.
+//
+// Top-level folders become areas, their subfolders categories, and the
+// folders below those entries. Folders without any subfolders are treated as
+// a single entry. Larger folders get lower numbers, and anything which
+// doesn't fit is grouped under "Other".
+func ProposePlan(source string, exclude ...string) (plan *Plan, err error) {
+	root, err := scanPlanFolder(source, "", exclude)
+	if err != nil {
+		return
+	}
+
+	plan = &Plan{Source: source}
+	if root == nil {
+		return
+	}
+
+	plan.Unmapped = append(plan.Unmapped, root.files...)
+
+	areas, overflow := splitPlanFolders(root.children, 9)
+	for n, area := range areas {
+		id := jdex.ACID{Area: jdex.ACIDCharset[n+1]}
+		plan.Items = append(plan.Items, PlanItem{ID: id, Name: area.name, Path: area.path})
+		plan.proposeCategories(id, area, area.children)
+	}
+
+	if len(overflow) > 0 {
+		id := jdex.ACID{Area: '9'}
+		plan.Items = append(plan.Items, PlanItem{ID: id, Name: "Other"})
+		plan.proposeCategories(id, nil, overflow)
+	}
+
+	return
+}
+
+func (plan *Plan) proposeCategories(areaID jdex.ACID, area *planFolder, folders []*planFolder) {
+	if area != nil {
+		if len(folders) == 0 {
+			id := jdex.ACID{Area: areaID.Area, Category: "1"}
+			plan.Items = append(plan.Items, PlanItem{ID: id, Name: area.name})
+			plan.proposeEntries(id, nil, []*planFolder{area})
+			return
+		}
+
+		plan.Unmapped = append(plan.Unmapped, area.files...)
+	}
+
+	categories, overflow := splitPlanFolders(folders, 9)
+	for n, category := range categories {
+		id := jdex.ACID{Area: areaID.Area, Category: string(jdex.ACIDCharset[n+1])}
+		plan.Items = append(plan.Items, PlanItem{ID: id, Name: category.name, Path: category.path})
+		plan.proposeEntries(id, category, category.children)
+	}
+
+	if len(overflow) > 0 {
+		id := jdex.ACID{Area: areaID.Area, Category: "9"}
+		plan.Items = append(plan.Items, PlanItem{ID: id, Name: "Other"})
+		plan.proposeEntries(id, nil, overflow)
+	}
+}
+
+func (plan *Plan) proposeEntries(categoryID jdex.ACID, category *planFolder, folders []*planFolder) {
+	if category != nil {
+		if len(folders) == 0 {
+			folders = []*planFolder{category}
+		} else {
+			plan.Unmapped = append(plan.Unmapped, category.files...)
+		}
+	}
+
+	slices.SortStableFunc(folders, comparePlanFolders)
+	for n, entry := range folders {
+		if n >= 99 {
+			plan.unmapFolder(entry)
+			continue
+		}
+
+		id := jdex.ACID{
+			Area:     categoryID.Area,
+			Category: categoryID.Category,
+			Entry:    fmt.Sprintf("%02d", n+1),
+		}
+		plan.Items = append(plan.Items, PlanItem{ID: id, Name: entry.name, Path: entry.path})
+	}
+}
+
+func (plan *Plan) unmapFolder(folder *planFolder) {
+	plan.Unmapped = append(plan.Unmapped, folder.files...)
+	for _, child := range folder.children {
+		plan.unmapFolder(child)
+	}
+}
+
+// Splits folders into the ones getting their own slot and the ones which
+// need to share the last slot.
+func splitPlanFolders(folders []*planFolder, slots int) (kept []*planFolder, overflow []*planFolder) {
+	slices.SortStableFunc(folders, comparePlanFolders)
+	if len(folders) <= slots {
+		return folders, nil
+	}
+
+	return folders[:slots-1], folders[slots-1:]
+}
+
+func comparePlanFolders(a, b *planFolder) int {
+	return cmp.Or(
+		cmp.Compare(b.size, a.size),
+		strings.Compare(a.name, b.name),
+	)
+}
+
+func scanPlanFolder(root string, rel string, exclude []string) (folder *planFolder, err error) {
+	full := filepath.Join(root, rel)
+	for _, excluded := range exclude {
+		if same, _ := samePath(full, excluded); same {
+			return nil, nil
+		}
+	}
+
+	dirEntries, err := os.ReadDir(full)
+	if err != nil {
+		return
+	}
+
+	folder = &planFolder{name: filepath.Base(full), path: rel}
+	for _, dirEntry := range dirEntries {
+		if strings.HasPrefix(dirEntry.Name(), ".") {
+			continue
+		}
+
+		childRel := filepath.Join(rel, dirEntry.Name())
+		if !dirEntry.IsDir() {
+			info, infoErr := dirEntry.Info()
+			if infoErr == nil {
+				folder.size += info.Size()
+			}
+			folder.files = append(folder.files, childRel)
+			continue
+		}
+
+		child, childErr := scanPlanFolder(root, childRel, exclude)
+		if childErr != nil {
+			return nil, childErr
+		}
+		if child == nil {
+			continue
+		}
+
+		folder.size += child.size
+		folder.children = append(folder.children, child)
+	}
+
+	return
+}
+
+func samePath(a, b string) (bool, error) {
+	a, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+
+	b, err = filepath.Abs(b)
+	if err != nil {
+		return false, err
+	}
+
+	return a == b, nil
+}
+
+// Write the plan in its editable text form.
+func WritePlan(plan *Plan, w io.Writer) (err error) {
+	_, err = fmt.Fprintf(w, "// Reorganisation plan. Edit IDs and names, or delete lines to leave\n"+
+		"// folders where they are. Paths are relative to the source.\n"+
+		"Source: %s\n", plan.Source)
+	if err != nil {
+		return
+	}
+
+	for _, item := range plan.Items {
+		var line string
+		switch {
+		case item.ID.Entry != "":
+			line = fmt.Sprintf("    %s %s", item.ID.String(), item.Name)
+		case item.ID.Category != "":
+			line = fmt.Sprintf("  %s %s", item.ID.CategoryString(), item.Name)
+		default:
+			line = fmt.Sprintf("%s %s", item.ID.AreaString(), item.Name)
+		}
+
+		if item.Path != "" {
+			line += " <- " + strconv.Quote(item.Path)
+		}
+
+		_, err = fmt.Fprintln(w, line)
+		if err != nil {
+			return
+		}
+	}
+
+	for _, unmapped := range plan.Unmapped {
+		_, err = fmt.Fprintf(w, "// unmapped: %s\n", unmapped)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Read a plan previously written with WritePlan.
+func ReadPlan(r io.Reader) (plan *Plan, err error) {
+	plan = &Plan{}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if unmapped, ok := strings.CutPrefix(line, "// unmapped: "); ok {
+			plan.Unmapped = append(plan.Unmapped, unmapped)
+			continue
+		}
+
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		if matches := planSourceRegex.FindStringSubmatch(line); matches != nil {
+			plan.Source = matches[1]
+			continue
+		}
+
+		item, itemErr := readPlanItem(line)
+		if itemErr != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrPlanParse, lineNumber, itemErr)
+		}
+
+		plan.Items = append(plan.Items, item)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if plan.Source == "" {
+		return nil, fmt.Errorf("%w: missing source", ErrPlanParse)
+	}
+
+	return
+}
+
+func readPlanItem(line string) (item PlanItem, err error) {
+	matches := planItemRegex.FindStringSubmatch(line)
+	if matches == nil {
+		err = errors.New("expected an ID and a name")
+		return
+	}
+
+	item.Name = matches[2]
+	if matches[3] != "" {
+		item.Path, err = strconv.Unquote(matches[3])
+		if err != nil {
+			return
+		}
+	}
+
+	id := matches[1]
+	switch {
+	case planAreaRegex.MatchString(id):
+		area := planAreaRegex.FindStringSubmatch(id)
+		if area[1] != area[2] {
+			err = fmt.Errorf("%q is not a valid area", id)
+			return
+		}
+		item.ID = jdex.ACID{Area: area[1][0]}
+	case planCategoryRegex.MatchString(id) && len(id) > 1:
+		item.ID = jdex.ACID{Area: id[0], Category: id[1:]}
+	default:
+		item.ID, err = jdex.ParseACID(id)
+	}
+
+	return
+}
+
+// Apply a plan to the store, moving the source folders into place and
+// writing the updated index.
+//
+// Every step taken is written to log so it can be undone with RollbackPlan.
+// If applying fails part way through, the steps taken so far are rolled back,
+// and the index is put back as it was.
+func (store *Store) ApplyPlan(plan *Plan, log io.Writer) (err error) {
+	err = store.checkPlan(plan)
+	if err != nil {
+		return
+	}
+
+	before := store.Index.Snapshot()
+
+	indexPath, err := store.IndexPath()
+	if err != nil {
+		return
+	}
+
+	oldIndex, err := os.ReadFile(indexPath)
+	if err != nil {
+		return
+	}
+
+	var steps []planStep
+	logger := json.NewEncoder(log)
+	record := func(step planStep) error {
+		steps = append(steps, step)
+		if step.Op != planStepIndex {
+			store.recordStep(step)
+		}
+
+		return logger.Encode(step)
+	}
+
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, rollbackPlanSteps(steps))
+			store.Index.Replace(before)
+		}
+	}()
+
+	err = record(planStep{Op: planStepIndex, Path: indexPath, Index: string(oldIndex)})
+	if err != nil {
+		return
+	}
+
+	sourceDirs := map[string]bool{}
+	for _, item := range plan.Items {
+		err = store.applyPlanItem(plan, item, record)
+		if err != nil {
+			return
+		}
+
+		if item.Path != "" {
+			sourceDirs[filepath.Join(plan.Source, item.Path)] = true
+		}
+	}
+
+	err = store.Save()
+	if err != nil {
+		return
+	}
+
+	// remove the husks of areas and categories, deepest first
+	for _, dir := range slices.Backward(slices.Sorted(maps.Keys(sourceDirs))) {
+		if os.Remove(dir) == nil {
+			err = record(planStep{Op: planStepRmdir, Path: dir})
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+func (store *Store) checkPlan(plan *Plan) error {
+	for _, item := range plan.Items {
+		switch {
+		case item.ID.Entry != "":
+			if _, err := store.Index.Entry(item.ID); err == nil {
+				return fmt.Errorf("%w: entry %q already exists", ErrPlanConflict, item.ID.String())
+			}
+
+			source := filepath.Join(plan.Source, item.Path)
+			if info, err := os.Stat(source); err != nil || !info.IsDir() {
+				return fmt.Errorf("%w: %q is not a folder", ErrPlanConflict, source)
+			}
+		case item.ID.Category != "":
+			name, err := store.Index.CategoryName(item.ID)
+			if err == nil && name != item.Name {
+				return fmt.Errorf("%w: category %q is already named %q",
+					ErrPlanConflict, item.ID.CategoryString(), name)
+			}
+		default:
+			name, err := store.Index.AreaName(item.ID)
+			if err == nil && name != item.Name {
+				return fmt.Errorf("%w: area %q is already named %q",
+					ErrPlanConflict, item.ID.AreaString(), name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (store *Store) applyPlanItem(plan *Plan, item PlanItem, record func(planStep) error) (err error) {
+	switch {
+	case item.ID.Entry != "":
+		entry := jdex.Entry{ID: item.ID, Name: item.Name}
+		if info, statErr := os.Stat(filepath.Join(plan.Source, item.Path)); statErr == nil {
+			entry.Created = info.ModTime().Truncate(time.Second)
+		}
+
+		err = store.Index.PutEntry(entry)
+		if err != nil {
+			return
+		}
+
+		var entryPath string
+		entryPath, err = store.EntryPath(item.ID)
+		if err != nil {
+			return
+		}
+
+		err = store.mkdirForPlan(filepath.Dir(entryPath), record)
+		if err != nil {
+			return
+		}
+
+		err = os.Rename(filepath.Join(plan.Source, item.Path), entryPath)
+		if err != nil {
+			return
+		}
+
+		return record(planStep{Op: planStepMove, From: filepath.Join(plan.Source, item.Path), To: entryPath})
+	case item.ID.Category != "":
+		return store.Index.PutCategory(item.ID, item.Name)
+	default:
+		return store.Index.PutArea(item.ID, item.Name)
+	}
+}
+
+// Create a directory and any missing parents, recording each one made.
+func (store *Store) mkdirForPlan(dir string, record func(planStep) error) error {
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+
+	if err := store.mkdirForPlan(filepath.Dir(dir), record); err != nil {
+		return err
+	}
+
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return err
+	}
+
+	return record(planStep{Op: planStepMkdir, Path: dir})
+}
+
+// Undo a reorganisation using the log written by Store.ApplyPlan.
+func RollbackPlan(log io.Reader) error {
+	var steps []planStep
+
+	decoder := json.NewDecoder(log)
+	for {
+		var step planStep
+		err := decoder.Decode(&step)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		steps = append(steps, step)
+	}
+
+	return rollbackPlanSteps(steps)
+}
+
+func rollbackPlanSteps(steps []planStep) error {
+	var errs []error
+	for _, step := range slices.Backward(steps) {
+		var err error
+		switch step.Op {
+		case planStepIndex:
+			err = os.WriteFile(step.Path, []byte(step.Index), 0644)
+		case planStepMkdir:
+			err = os.Remove(step.Path)
+		case planStepMove:
+			err = os.Rename(step.To, step.From)
+		case planStepRmdir:
+			err = os.Mkdir(step.Path, 0755)
+			if errors.Is(err, fs.ErrExist) {
+				err = nil
+			}
+		default:
+			err = fmt.Errorf("unknown step %q", step.Op)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,212 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeMessyDocuments(t *testing.T) string {
+	root := t.TempDir()
+	files := map[string]string{
+		"Finance/Banking/Savings/statement.txt": "lots and lots of money",
+		"Finance/Banking/Current/statement.txt": "some money",
+		"Finance/Taxes/2024/return.txt":         "tax",
+		"Finance/notes.txt":                     "loose",
+		"Recipes/pancakes.txt":                  "flour, eggs, milk",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+func Test_ProposePlan_MapsByDepthAndSize(t *testing.T) {
+	plan, err := jdfs.ProposePlan(makeMessyDocuments(t))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	var ids []string
+	for _, item := range plan.Items {
+		switch {
+		case item.ID.Entry != "":
+			ids = append(ids, item.ID.String()+" "+item.Name)
+		case item.ID.Category != "":
+			ids = append(ids, item.ID.CategoryString()+" "+item.Name)
+		default:
+			ids = append(ids, item.ID.AreaString()+" "+item.Name)
+		}
+	}
+
+	assert.Equal(t, []string{
+		"10-19 Finance",
+		"11 Banking",
+		"11.01 Savings",
+		"11.02 Current",
+		"12 Taxes",
+		"12.01 2024",
+		"20-29 Recipes",
+		"21 Recipes",
+		"21.01 Recipes",
+	}, ids)
+	assert.Equal(t, []string{filepath.Join("Finance", "notes.txt")}, plan.Unmapped)
+}
+
+func Test_Plan_RoundTrip(t *testing.T) {
+	plan, err := jdfs.ProposePlan(makeMessyDocuments(t))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	buffer := bytes.Buffer{}
+	if !assert.NoError(t, jdfs.WritePlan(plan, &buffer)) {
+		t.FailNow()
+	}
+
+	read, err := jdfs.ReadPlan(&buffer)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, plan, read)
+}
+
+func Test_Store_ApplyPlan_Rollback(t *testing.T) {
+	source := makeMessyDocuments(t)
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	plan, err := jdfs.ProposePlan(source)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	log := bytes.Buffer{}
+	if !assert.NoError(t, store.ApplyPlan(plan, &log)) {
+		t.FailNow()
+	}
+
+	savingsPath, err := store.EntryPath(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(savingsPath, "statement.txt"))
+	assert.NoDirExists(t, filepath.Join(source, "Recipes"))
+	assert.FileExists(t, filepath.Join(source, "Finance", "notes.txt"))
+
+	reopened, err := jdfs.OpenStore(store.Root)
+	if assert.NoError(t, err) {
+		entry, err := reopened.Index.Entry(jdex.MustParseACID("21.01"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Recipes", entry.Name)
+	}
+
+	if !assert.NoError(t, jdfs.RollbackPlan(&log)) {
+		t.FailNow()
+	}
+
+	assert.FileExists(t, filepath.Join(source, "Finance", "Banking", "Savings", "statement.txt"))
+	assert.FileExists(t, filepath.Join(source, "Recipes", "pancakes.txt"))
+	assert.NoDirExists(t, savingsPath)
+}
+
+// Fails once it has been written to a number of times.
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.writes == 0 {
+		return 0, os.ErrClosed
+	}
+
+	w.writes--
+	return len(p), nil
+}
+
+func Test_Store_ApplyPlan_FailRestoresIndex(t *testing.T) {
+	source := makeMessyDocuments(t)
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	plan, err := jdfs.ProposePlan(source)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	err = store.ApplyPlan(plan, &failingWriter{writes: 3})
+	assert.ErrorIs(t, err, os.ErrClosed)
+
+	_, err = store.Index.AreaName(jdex.MustParseACID("11.01"))
+	assert.ErrorIs(t, err, jdex.ErrAreaNotFound)
+	assert.FileExists(t, filepath.Join(source, "Finance", "Banking", "Savings", "statement.txt"))
+}
+
+func Test_Store_ApplyPlan_Undo(t *testing.T) {
+	source := makeMessyDocuments(t)
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	plan, err := jdfs.ProposePlan(source)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	err = store.Record("reorganize", func() error {
+		return store.ApplyPlan(plan, &bytes.Buffer{})
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	savingsPath, err := store.EntryPath(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+
+	_, err = store.Undo(1)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(source, "Finance", "Banking", "Savings", "statement.txt"))
+	assert.FileExists(t, filepath.Join(source, "Recipes", "pancakes.txt"))
+	assert.NoDirExists(t, savingsPath)
+
+	_, err = store.Index.Entry(jdex.MustParseACID("11.01"))
+	assert.ErrorIs(t, err, jdex.ErrEntryNotFound)
+
+	_, err = store.Redo(1)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(savingsPath, "statement.txt"))
+	assert.NoDirExists(t, filepath.Join(source, "Recipes"))
+}
@@ -0,0 +1,538 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// SearchIndex is an inverted index over the notes, file names and metadata of
+// a store's entries. It's kept alongside the system index and brought up to
+// date before each search, only reading what changed since.
+type SearchIndex struct {
+	Format    int                       `json:"format"`    // Layout version of the search index.
+	Documents map[string]SearchDocument `json:"documents"` // Documents by key.
+	Postings  map[string]map[string]int `json:"postings"`  // Occurrences of each term, by term and then document key.
+}
+
+// Something searched as part of an entry: its notes, the names of the files
+// in one of its directories, or its name, tags and metadata.
+type SearchDocument struct {
+	ID     string   `json:"id"`             // Entry the document belongs to.
+	Kind   string   `json:"kind"`           // One of the Search kinds.
+	Path   string   `json:"path,omitempty"` // File or directory, relative to the store root.
+	Stamp  string   `json:"stamp"`          // Changes whenever the document does.
+	Length int      `json:"length"`         // Number of terms in the document.
+	Terms  []string `json:"terms"`          // Distinct terms in the document.
+}
+
+// An entry matching a search.
+type SearchHit struct {
+	ID       jdex.ACID
+	Name     string
+	Score    float64
+	Snippets []SearchSnippet // Where the terms were found, best first.
+}
+
+// A line where search terms were found.
+type SearchSnippet struct {
+	Kind string
+	Text string
+}
+
+// Kinds of search documents.
+const (
+	SearchNotes    = "notes"
+	SearchFiles    = "files"
+	SearchMetadata = "metadata"
+)
+
+// Name of the search index, kept alongside the system index.
+const SearchIndexFilename = "Search Index.json"
+
+// Layout version of search indexes made by this version of rzjd. Indexes of
+// other versions are rebuilt.
+const SearchIndexFormat = 1
+
+// How much a match counts for in each kind of document.
+var searchWeights = map[string]float64{
+	SearchMetadata: 2,
+	SearchFiles:    1.5,
+	SearchNotes:    1,
+}
+
+// Longest snippet shown, in characters.
+const snippetLength = 100
+
+// Tuning for BM25 ranking.
+const (
+	rankSaturation = 1.2
+	rankLength     = 0.75
+)
+
+var ErrEmptySearch = errors.New("search has no terms")
+
+// Get the path to the search index.
+func (store *Store) SearchIndexPath() (string, error) {
+	return store.SystemFilePath(SearchIndexFilename)
+}
+
+// Read the search index and bring it up to date, saving it if anything
+// changed. A missing or outdated search index is rebuilt from scratch.
+func (store *Store) UpdateSearchIndex() (searchIndex *SearchIndex, err error) {
+	searchIndexPath, err := store.SearchIndexPath()
+	if err != nil {
+		return
+	}
+
+	searchIndex = &SearchIndex{}
+	data, err := os.ReadFile(searchIndexPath)
+	if err == nil {
+		err = json.Unmarshal(data, searchIndex)
+	}
+
+	if err != nil || searchIndex.Format != SearchIndexFormat {
+		if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.As(err, new(*json.SyntaxError)) {
+			return nil, err
+		}
+
+		searchIndex = &SearchIndex{Format: SearchIndexFormat}
+	}
+
+	if searchIndex.Documents == nil {
+		searchIndex.Documents = map[string]SearchDocument{}
+	}
+
+	if searchIndex.Postings == nil {
+		searchIndex.Postings = map[string]map[string]int{}
+	}
+
+	changed, err := store.refreshSearchIndex(searchIndex)
+	if err != nil || !changed {
+		return
+	}
+
+	data, err = json.Marshal(searchIndex)
+	if err != nil {
+		return
+	}
+
+	err = os.WriteFile(searchIndexPath, data, 0644)
+	return
+}
+
+// Bring the search index up to date with the store, re-reading documents
+// whose stamp changed and dropping those which are gone.
+func (store *Store) refreshSearchIndex(searchIndex *SearchIndex) (changed bool, err error) {
+	entryPaths := map[string]jdex.ACID{}
+	for id := range store.Index.AllEntries() {
+		if slices.Contains(jdex.ProtectedACIDs, id.String()) {
+			continue
+		}
+
+		entryPath, err := store.EntryPath(id)
+		if err != nil {
+			return false, err
+		}
+
+		entryPaths[entryPath] = id
+	}
+
+	seen := map[string]bool{}
+	refresh := func(doc SearchDocument) error {
+		key := doc.Kind + ":" + doc.Path
+		if doc.Kind == SearchMetadata {
+			key = doc.Kind + ":" + doc.ID
+		}
+
+		seen[key] = true
+		if old, ok := searchIndex.Documents[key]; ok && old.ID == doc.ID && old.Stamp == doc.Stamp {
+			return nil
+		}
+
+		lines, err := store.searchLines(doc)
+		if err != nil {
+			return err
+		}
+
+		searchIndex.put(key, doc, tokenize(strings.Join(lines, "\n")))
+		changed = true
+		return nil
+	}
+
+	for entryPath, id := range entryPaths {
+		entry, _ := store.Index.Entry(id)
+		err = refresh(SearchDocument{ID: id.String(), Kind: SearchMetadata, Stamp: metadataStamp(entry)})
+		if err != nil {
+			return
+		}
+
+		err = filepath.WalkDir(entryPath, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if p == entryPath && errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+
+				return err
+			}
+
+			if _, ok := entryPaths[p]; ok && p != entryPath {
+				return fs.SkipDir
+			}
+
+			isNotes := p == filepath.Join(entryPath, EntryIndexFilename)
+			if !d.IsDir() && !isNotes {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(store.Root, p)
+			if err != nil {
+				return err
+			}
+
+			doc := SearchDocument{ID: id.String(), Kind: SearchFiles, Path: filepath.ToSlash(rel)}
+			doc.Stamp = info.ModTime().Format(time.RFC3339Nano)
+			if isNotes {
+				doc.Kind = SearchNotes
+				doc.Stamp = fmt.Sprintf("%s %d", doc.Stamp, info.Size())
+			}
+
+			return refresh(doc)
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	for key := range searchIndex.Documents {
+		if !seen[key] {
+			searchIndex.remove(key)
+			changed = true
+		}
+	}
+
+	return
+}
+
+// Get the lines of text a document is made of.
+func (store *Store) searchLines(doc SearchDocument) (lines []string, err error) {
+	switch doc.Kind {
+	case SearchNotes:
+		var data []byte
+		data, err = os.ReadFile(filepath.Join(store.Root, filepath.FromSlash(doc.Path)))
+		lines = strings.Split(string(data), "\n")
+	case SearchFiles:
+		dir := filepath.Join(store.Root, filepath.FromSlash(doc.Path))
+
+		var dirEntries []os.DirEntry
+		dirEntries, err = os.ReadDir(dir)
+		for _, dirEntry := range dirEntries {
+			if dirEntry.Name() == EntryIndexFilename && dirEntry.Type().IsRegular() {
+				continue
+			}
+
+			// sub-entries are searched on their own
+			if dirEntry.IsDir() {
+				located, locateErr := store.Locate(filepath.Join(dir, dirEntry.Name()))
+				if locateErr == nil && located.String() != doc.ID {
+					continue
+				}
+			}
+
+			lines = append(lines, dirEntry.Name())
+		}
+	case SearchMetadata:
+		var id jdex.ACID
+		id, err = jdex.ParseACID(doc.ID)
+		if err != nil {
+			return
+		}
+
+		var entry jdex.Entry
+		entry, err = store.Index.Entry(id)
+		lines = metadataLines(entry)
+	}
+
+	return
+}
+
+// Search the entries' notes, file names and metadata for every term in
+// query. Terms ending with `*` match any term they start.
+func (store *Store) Search(query string) (hits []SearchHit, err error) {
+	searchIndex, err := store.UpdateSearchIndex()
+	if err != nil {
+		return
+	}
+
+	queryTerms := searchIndex.expand(query)
+	if len(queryTerms) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrEmptySearch, query)
+	}
+
+	totalLength := 0
+	for _, doc := range searchIndex.Documents {
+		totalLength += doc.Length
+	}
+
+	docCount := float64(len(searchIndex.Documents))
+	averageLength := float64(totalLength) / max(docCount, 1)
+
+	type entryMatch struct {
+		score float64
+		found []bool              // Which query terms were found.
+		docs  map[string][]string // Terms found in each document.
+		ranks map[string]float64  // Score of each document.
+	}
+
+	matches := map[string]*entryMatch{}
+	for n, terms := range queryTerms {
+		for _, term := range terms {
+			postings := searchIndex.Postings[term]
+			df := float64(len(postings))
+			idf := math.Log(1 + (docCount-df+0.5)/(df+0.5))
+
+			for key, tf := range postings {
+				doc := searchIndex.Documents[key]
+				match := matches[doc.ID]
+				if match == nil {
+					match = &entryMatch{
+						found: make([]bool, len(queryTerms)),
+						docs:  map[string][]string{},
+						ranks: map[string]float64{},
+					}
+					matches[doc.ID] = match
+				}
+
+				norm := 1 - rankLength + rankLength*float64(doc.Length)/max(averageLength, 1)
+				score := searchWeights[doc.Kind] * idf * float64(tf) * (rankSaturation + 1) / (float64(tf) + rankSaturation*norm)
+
+				match.score += score
+				match.found[n] = true
+				match.docs[key] = append(match.docs[key], term)
+				match.ranks[key] += score
+			}
+		}
+	}
+
+	for idString, match := range matches {
+		if slices.Contains(match.found, false) {
+			continue
+		}
+
+		id, err := jdex.ParseACID(idString)
+		if err != nil {
+			continue
+		}
+
+		entry, err := store.Index.Entry(id)
+		if err != nil {
+			continue
+		}
+
+		hit := SearchHit{ID: id, Name: entry.Name, Score: match.score}
+
+		keys := slices.Collect(maps.Keys(match.docs))
+		slices.SortFunc(keys, func(a, b string) int {
+			return cmp.Or(cmp.Compare(match.ranks[b], match.ranks[a]), cmp.Compare(a, b))
+		})
+
+		for _, key := range keys {
+			doc := searchIndex.Documents[key]
+			lines, err := store.searchLines(doc)
+			if err != nil {
+				continue
+			}
+
+			for _, line := range matchingLines(lines, match.docs[key]) {
+				hit.Snippets = append(hit.Snippets, SearchSnippet{Kind: doc.Kind, Text: line})
+			}
+		}
+
+		hits = append(hits, hit)
+	}
+
+	slices.SortFunc(hits, func(a, b SearchHit) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), a.ID.Compare(b.ID))
+	})
+	return
+}
+
+// Add or replace a document and its terms.
+func (searchIndex *SearchIndex) put(key string, doc SearchDocument, terms []string) {
+	searchIndex.remove(key)
+
+	counts := map[string]int{}
+	for _, term := range terms {
+		counts[term]++
+	}
+
+	doc.Length = len(terms)
+	doc.Terms = make([]string, 0, len(counts))
+	for term, count := range counts {
+		if searchIndex.Postings[term] == nil {
+			searchIndex.Postings[term] = map[string]int{}
+		}
+
+		searchIndex.Postings[term][key] = count
+		doc.Terms = append(doc.Terms, term)
+	}
+
+	slices.Sort(doc.Terms)
+	searchIndex.Documents[key] = doc
+}
+
+func (searchIndex *SearchIndex) remove(key string) {
+	for _, term := range searchIndex.Documents[key].Terms {
+		delete(searchIndex.Postings[term], key)
+		if len(searchIndex.Postings[term]) == 0 {
+			delete(searchIndex.Postings, term)
+		}
+	}
+
+	delete(searchIndex.Documents, key)
+}
+
+// Get the indexed terms each word of query matches, dropping words which
+// don't make any terms.
+func (searchIndex *SearchIndex) expand(query string) (expanded [][]string) {
+	for _, word := range strings.Fields(query) {
+		prefix := strings.HasSuffix(word, "*")
+
+		words := tokenize(word)
+		if len(words) == 0 {
+			continue
+		}
+
+		for n, term := range words {
+			if !prefix || n != len(words)-1 {
+				expanded = append(expanded, []string{term})
+				continue
+			}
+
+			var terms []string
+			for indexed := range searchIndex.Postings {
+				if strings.HasPrefix(indexed, term) {
+					terms = append(terms, indexed)
+				}
+			}
+
+			slices.Sort(terms)
+			expanded = append(expanded, terms)
+		}
+	}
+
+	return
+}
+
+// Split text into lower case terms made of letters and numbers.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Get the lines of an entry's name, tags and metadata.
+func metadataLines(entry jdex.Entry) (lines []string) {
+	lines = append(lines, entry.Name)
+	for _, tag := range entry.Tags {
+		lines = append(lines, "#"+tag)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(entry.Metadata)) {
+		lines = append(lines, fmt.Sprintf("%s: %s", key, entry.Metadata[key].String()))
+	}
+
+	return
+}
+
+func metadataStamp(entry jdex.Entry) string {
+	hash := fnv.New64a()
+	for _, line := range metadataLines(entry) {
+		hash.Write([]byte(line + "\n"))
+	}
+
+	return fmt.Sprintf("%x", hash.Sum64())
+}
+
+// Get the lines containing any of the terms, shortened around the first one
+// found.
+func matchingLines(lines []string, terms []string) (matched []string) {
+	for _, line := range lines {
+		lineTerms := tokenize(line)
+		if !slices.ContainsFunc(terms, func(term string) bool {
+			return slices.Contains(lineTerms, term)
+		}) {
+			continue
+		}
+
+		matched = append(matched, shorten(strings.TrimSpace(line), terms))
+	}
+
+	return
+}
+
+// Shorten a line to snippetLength characters, keeping the first term found
+// in view.
+func shorten(line string, terms []string) string {
+	runes := []rune(line)
+	if len(runes) <= snippetLength {
+		return line
+	}
+
+	lower := []rune(strings.ToLower(line))
+	at := 0
+	for _, term := range terms {
+		if i := strings.Index(string(lower), term); i >= 0 {
+			at = len([]rune(string(lower)[:i]))
+			break
+		}
+	}
+
+	start := max(0, min(at-snippetLength/3, len(runes)-snippetLength))
+	end := start + snippetLength
+
+	snippet := string(runes[start:end])
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+
+	if end < len(runes) {
+		snippet += "…"
+	}
+
+	return snippet
+}
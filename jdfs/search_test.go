@@ -0,0 +1,163 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSearchStore(t *testing.T) *jdfs.Store {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	store.Index.PutArea(jdex.ACID{Area: '1'}, "Finance")
+	store.Index.PutCategory(jdex.ACID{Area: '1', Category: "1"}, "Clients")
+
+	for _, entry := range []jdex.Entry{
+		{ID: jdex.MustParseACID("11.01"), Name: "Acme", Metadata: map[string]jdex.Value{"Status": jdex.StringValue("overdue")}},
+		{ID: jdex.MustParseACID("11.01+001"), Name: "Quarterly report"},
+		{ID: jdex.MustParseACID("11.02"), Name: "Globex", Tags: []string{"tax"}},
+	} {
+		if !assert.NoError(t, store.PutEntry(entry)) {
+			t.FailNow()
+		}
+	}
+
+	writeEntryFile(t, store, "11.01", jdfs.EntryIndexFilename, "Met with the accountant.\nThe invoice for March is overdue.\n")
+	writeEntryFile(t, store, "11.01", "march-invoice.pdf", "")
+	writeEntryFile(t, store, "11.01+001", "summary.txt", "")
+	writeEntryFile(t, store, "11.02", jdfs.EntryIndexFilename, "Ask the accountant about tax returns.\n")
+	writeEntryFile(t, store, "11.02", filepath.Join("Receipts", "invoice-2024.pdf"), "")
+
+	return store
+}
+
+func writeEntryFile(t *testing.T, store *jdfs.Store, id string, name string, content string) {
+	entryPath, err := store.EntryPath(jdex.MustParseACID(id))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	p := filepath.Join(entryPath, name)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+	assert.NoError(t, os.WriteFile(p, []byte(content), 0644))
+}
+
+func hitIDs(hits []jdfs.SearchHit) (ids []string) {
+	for _, hit := range hits {
+		ids = append(ids, hit.ID.String())
+	}
+
+	return
+}
+
+func Test_Store_Search(t *testing.T) {
+	store := newSearchStore(t)
+
+	hits, err := store.Search("accountant")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"11.01", "11.02"}, hitIDs(hits))
+
+	hits, err = store.Search("invoice march")
+	assert.NoError(t, err)
+	if assert.Equal(t, []string{"11.01"}, hitIDs(hits)) {
+		assert.Contains(t, hits[0].Snippets, jdfs.SearchSnippet{Kind: jdfs.SearchFiles, Text: "march-invoice.pdf"})
+		assert.Contains(t, hits[0].Snippets, jdfs.SearchSnippet{Kind: jdfs.SearchNotes, Text: "The invoice for March is overdue."})
+	}
+
+	hits, err = store.Search("summary")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"11.01+001"}, hitIDs(hits))
+
+	hits, err = store.Search("overdue")
+	assert.NoError(t, err)
+	if assert.Equal(t, []string{"11.01"}, hitIDs(hits)) {
+		assert.Equal(t, jdfs.SearchSnippet{Kind: jdfs.SearchMetadata, Text: "Status: overdue"}, hits[0].Snippets[0])
+	}
+
+	hits, err = store.Search("quart*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"11.01+001"}, hitIDs(hits))
+
+	_, err = store.Search("  ,. ")
+	assert.ErrorIs(t, err, jdfs.ErrEmptySearch)
+
+	searchIndexPath, _ := store.SearchIndexPath()
+	assert.FileExists(t, searchIndexPath)
+}
+
+func Test_Store_Search_Ranking(t *testing.T) {
+	store := newSearchStore(t)
+
+	hits, err := store.Search("tax")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"11.02"}, hitIDs(hits))
+
+	hits, err = store.Search("invoice")
+	assert.NoError(t, err)
+	if assert.Len(t, hits, 2) {
+		assert.Equal(t, "11.01", hits[0].ID.String())
+		assert.Greater(t, hits[0].Score, hits[1].Score)
+	}
+}
+
+func Test_Store_Search_Incremental(t *testing.T) {
+	store := newSearchStore(t)
+
+	_, err := store.Search("accountant")
+	assert.NoError(t, err)
+
+	// make sure the modification time changes
+	later := time.Now().Add(time.Minute)
+	writeEntryFile(t, store, "11.02", jdfs.EntryIndexFilename, "Nothing about bookkeeping here.\n")
+	notesPath, _ := store.NotesPath(jdex.MustParseACID("11.02"))
+	assert.NoError(t, os.Chtimes(notesPath, later, later))
+
+	hits, err := store.Search("accountant")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"11.01"}, hitIDs(hits))
+
+	hits, err = store.Search("bookkeeping")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"11.02"}, hitIDs(hits))
+
+	entryPath, _ := store.EntryPath(jdex.MustParseACID("11.01"))
+	assert.NoError(t, os.Remove(filepath.Join(entryPath, "march-invoice.pdf")))
+	assert.NoError(t, os.Chtimes(entryPath, later, later))
+
+	hits, err = store.Search("pdf")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"11.02"}, hitIDs(hits))
+
+	reopened, err := jdfs.OpenStore(store.Root)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	hits, err = reopened.Search("bookkeeping")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"11.02"}, hitIDs(hits))
+}
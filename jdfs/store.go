@@ -21,7 +21,11 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/itisrazza/rzjd/jdex"
 	"github.com/itisrazza/rzjd/jdex/jdexfile"
@@ -30,8 +34,13 @@ import (
 /*
  */
 type Store struct {
-	Root  string     // Path to where the store is located.
-	Index jdex.Index // Pointer to index to use for name lookup.
+	Root   string      // Path to where the store is located.
+	Index  *jdex.Index // Pointer to index to use for name lookup.
+	Marker Marker      // Store's identity, kept in its root.
+
+	operation *Operation // Operation being recorded, see Store.Record.
+	actor     auditActor // Who's making changes, for the audit log.
+	commitMu  sync.Mutex // Held while a transaction commits.
 }
 
 var ErrPathNotDir = errors.New("path is not a directory")
@@ -39,6 +48,10 @@ var ErrEntryNotFound = errors.New("entry was not found in index")
 
 const EntryIndexFilename = "Index.txt"
 
+// Name of the directory entries are archived to when no other is given, kept
+// alongside the system index.
+const ArchiveFilename = "Archive"
+
 func newStoreImpl(path string) (store *Store, err error) {
 	pathInfo, err := os.Stat(path)
 	if err != nil || !pathInfo.IsDir() {
@@ -64,6 +77,17 @@ func NewStore(path string) (store *Store, err error) {
 		return
 	}
 
+	store.Marker, err = NewMarker()
+	if err != nil {
+		return
+	}
+
+	err = WriteMarker(store.Root, store.Marker)
+	if err != nil {
+		err = fmt.Errorf("failed to create store marker: %w", err)
+		return
+	}
+
 	indexPath, err := store.IndexPath()
 	if err != nil {
 		err = fmt.Errorf("failed to create index file: %w", err)
@@ -77,12 +101,13 @@ func NewStore(path string) (store *Store, err error) {
 	}
 	defer indexFile.Close()
 
-	err = jdexfile.Write(&store.Index, indexFile)
+	err = jdexfile.Write(store.Index, indexFile)
 	if err != nil {
 		err = fmt.Errorf("failed to create index file: %w", err)
 		return
 	}
 
+	store.startAudit()
 	return
 }
 
@@ -93,23 +118,106 @@ func OpenStore(path string) (store *Store, err error) {
 		return
 	}
 
-	indexPath, err := store.IndexPath()
+	// the new index has the system entries' names, so can find the journal
+	// before the store's own index is read
+	err = store.recoverTransaction()
 	if err != nil {
 		return
 	}
 
-	indexFile, err := os.Open(indexPath)
+	err = store.readIndex()
+	if err != nil {
+		return
+	}
+
+	err = store.readMarker()
 	if err != nil {
 		return
 	}
+
+	store.startAudit()
+	err = store.backfillTimestamps()
+	return
+}
+
+// Open the store at the given path only to look things up in it, such as for
+// a shell prompt. Unlike OpenStore, it leaves the store as it is: nothing is
+// recovered or filled in, and changes mustn't be made through it.
+func OpenStoreReadOnly(path string) (store *Store, err error) {
+	store, err = newStoreImpl(path)
+	if err != nil {
+		return
+	}
+
+	err = store.readIndex()
+	return
+}
+
+// Read the store's index from its file.
+func (store *Store) readIndex() error {
+	indexPath, err := store.IndexPath()
+	if err != nil {
+		return err
+	}
+
+	indexFile, err := os.Open(indexPath)
+	if err != nil {
+		return err
+	}
 	defer indexFile.Close()
 
 	store.Index, err = jdexfile.Read(indexFile)
+	return err
+}
+
+// Read the store's marker, giving stores from before markers were kept a new
+// one.
+func (store *Store) readMarker() (err error) {
+	store.Marker, err = ReadMarker(store.Root)
+	if !errors.Is(err, os.ErrNotExist) {
+		return
+	}
+
+	store.Marker, err = NewMarker()
 	if err != nil {
 		return
 	}
 
-	return
+	return WriteMarker(store.Root, store.Marker)
+}
+
+// Give entries from before timestamps were kept the modification time of
+// their directory. They're only kept in memory until the index is next saved,
+// so opening a store to read it doesn't write to it.
+func (store *Store) backfillTimestamps() error {
+	for entryID := range store.Index.AllEntries() {
+		entry, _ := store.Index.Entry(entryID)
+		if !entry.Created.IsZero() {
+			continue
+		}
+
+		entryPath, err := store.EntryPath(entryID)
+		if err != nil {
+			return err
+		}
+
+		info, err := os.Stat(entryPath)
+		if err != nil {
+			continue
+		}
+
+		entry.Created = info.ModTime().Truncate(time.Second)
+		if entry.Modified.IsZero() {
+			entry.Modified = entry.Created
+		}
+
+		err = store.Index.LoadEntry(entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // Get the path to the system index file.
@@ -117,6 +225,56 @@ func (store *Store) IndexPath() (entryPath string, err error) {
 	return store.EntryIndexPath(jdex.MustParseACID("00.00"))
 }
 
+// Get the path to a file kept alongside the system index.
+func (store *Store) SystemFilePath(name string) (systemFilePath string, err error) {
+	entryPath, err := store.EntryPath(jdex.MustParseACID("00.00"))
+	if err != nil {
+		return
+	}
+
+	systemFilePath = path.Join(entryPath, name)
+	return
+}
+
+// Write the index back to the system index file.
+func (store *Store) Save() (err error) {
+	indexPath, err := store.IndexPath()
+	if err != nil {
+		return
+	}
+
+	indexFile, err := CreateWithParents(indexPath)
+	if err != nil {
+		return
+	}
+	defer indexFile.Close()
+
+	return jdexfile.Write(store.Index, indexFile)
+}
+
+// Get the path to the directory of an area, category or entry.
+func (store *Store) Path(id jdex.ACID) (string, error) {
+	switch id.Level() {
+	case jdex.LevelArea:
+		return store.AreaPath(id)
+	case jdex.LevelCategory:
+		return store.CategoryPath(id)
+	}
+
+	return store.EntryPath(id)
+}
+
+// Get the path to the notes file of an area, category or entry.
+func (store *Store) NotesPath(id jdex.ACID) (notesPath string, err error) {
+	dirPath, err := store.Path(id)
+	if err != nil {
+		return
+	}
+
+	notesPath = path.Join(dirPath, EntryIndexFilename)
+	return
+}
+
 // Get the path to the area directory.
 func (store *Store) AreaPath(id jdex.ACID) (areaPath string, err error) {
 	areaName, err := store.Index.AreaName(id)
@@ -151,9 +309,15 @@ func (store *Store) CategoryPath(id jdex.ACID) (path_ string, err error) {
 	return
 }
 
-// Get the path to the entry directory.
+// Get the path to the entry directory. Sub-entries are kept inside their
+// parent entry's directory.
 func (store *Store) EntryPath(id jdex.ACID) (entryPath string, err error) {
-	categoryPath, err := store.CategoryPath(id)
+	var categoryPath string
+	if id.Sub != "" {
+		categoryPath, err = store.EntryPath(id.EntryID())
+	} else {
+		categoryPath, err = store.CategoryPath(id)
+	}
 	if err != nil {
 		return
 	}
@@ -168,6 +332,165 @@ func (store *Store) EntryPath(id jdex.ACID) (entryPath string, err error) {
 	return
 }
 
+// Add or update an entry, creating or renaming its directory to match, and
+// save the index.
+func (store *Store) PutEntry(entry jdex.Entry) (err error) {
+	tx, err := store.Begin()
+	if err != nil {
+		return
+	}
+
+	err = tx.PutEntry(entry)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	return tx.Commit()
+}
+
+// Add or rename a category, creating or renaming its directory to match, and
+// save the index.
+func (store *Store) PutCategory(id jdex.ACID, name string) (err error) {
+	tx, err := store.Begin()
+	if err != nil {
+		return
+	}
+
+	oldPath, oldErr := tx.CategoryPath(id)
+
+	err = tx.Index.PutCategory(id, name)
+	if err != nil {
+		return
+	}
+
+	categoryPath, err := tx.CategoryPath(id)
+	if err != nil {
+		return
+	}
+
+	if oldErr == nil && oldPath != categoryPath && exists(oldPath) {
+		tx.Move(oldPath, categoryPath)
+	}
+
+	tx.Mkdir(categoryPath)
+	return tx.Commit()
+}
+
+// Give an entry a new ID, moving its directory and those of its sub-entries
+// to match, and save the index. Returns the links which still point at the
+// old ID.
+func (store *Store) RenumberEntry(from jdex.ACID, to jdex.ACID) (dangling []jdex.Link, err error) {
+	tx, err := store.Begin()
+	if err != nil {
+		return
+	}
+
+	oldPath, err := tx.EntryPath(from)
+	if err != nil {
+		return
+	}
+
+	subs := slices.Collect(tx.Index.Children(from))
+	oldSubFilenames := make([]string, len(subs))
+	for n, subID := range subs {
+		sub, _ := tx.Index.Entry(subID)
+		oldSubFilenames[n] = EntryFilename(sub)
+	}
+
+	dangling, err = tx.Index.RenumberEntry(from, to)
+	if err != nil {
+		return
+	}
+
+	newPath, err := tx.EntryPath(to)
+	if err != nil {
+		return
+	}
+
+	if !exists(oldPath) {
+		err = tx.Commit()
+		return
+	}
+
+	tx.Move(oldPath, newPath)
+
+	// sub-entry directories move along with the entry, but are still named
+	// after its old ID
+	for n, subID := range subs {
+		newSubID := to
+		newSubID.Sub = subID.Sub
+
+		var newSubPath string
+		newSubPath, err = tx.EntryPath(newSubID)
+		if err != nil {
+			return
+		}
+
+		if exists(path.Join(oldPath, oldSubFilenames[n])) {
+			tx.Move(path.Join(newPath, oldSubFilenames[n]), newSubPath)
+		}
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// Move an entry's directory out of the store into archive, under the same
+// area and category directories, and remove it and its sub-entries from the
+// index. Returns the links which now point nowhere.
+func (store *Store) ArchiveEntry(id jdex.ACID, archive string) (dangling []jdex.Link, err error) {
+	tx, err := store.Begin()
+	if err != nil {
+		return
+	}
+
+	entryPath, err := tx.EntryPath(id)
+	if err != nil {
+		return
+	}
+
+	dangling, err = tx.Index.RemoveEntry(id)
+	if err != nil {
+		return
+	}
+
+	if exists(entryPath) {
+		var rel string
+		rel, err = filepath.Rel(store.Root, entryPath)
+		if err != nil {
+			return
+		}
+
+		tx.Move(entryPath, filepath.Join(archive, rel))
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// Read the `[[AC.ID]]` links from every entry's notes into the index.
+func (store *Store) ScanNoteLinks() error {
+	for entryID := range store.Index.AllEntries() {
+		notesPath, err := store.EntryIndexPath(entryID)
+		if err != nil {
+			return err
+		}
+
+		notes, err := os.ReadFile(notesPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		err = store.Index.PutNoteLinks(entryID, jdex.FindWikiLinks(string(notes)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (store *Store) EntryIndexPath(id jdex.ACID) (entryIndexPath string, err error) {
 	entryPath, err := store.EntryPath(id)
 	if err != nil {
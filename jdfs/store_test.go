@@ -15,3 +15,136 @@
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 package jdfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Store_SubEntryPaths(t *testing.T) {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	id := jdex.MustParseACID("11.01")
+	store.Index.PutArea(id, "Finance")
+	store.Index.PutCategory(id, "Clients")
+	assert.NoError(t, store.PutEntry(jdex.Entry{ID: id, Name: "Acme"}))
+
+	sub := jdex.MustParseACID("11.01+001")
+	assert.NoError(t, store.PutEntry(jdex.Entry{ID: sub, Name: "Invoice"}))
+
+	subPath, err := store.Path(sub)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(store.Root, "10-19 Finance", "11 Clients", "11.01 Acme", "11.01+001 Invoice"), subPath)
+	assert.DirExists(t, subPath)
+
+	_, err = store.RenumberEntry(id, jdex.MustParseACID("11.02"))
+	assert.NoError(t, err)
+
+	subPath, err = store.Path(jdex.MustParseACID("11.02+001"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(store.Root, "10-19 Finance", "11 Clients", "11.02 Acme", "11.02+001 Invoice"), subPath)
+	assert.DirExists(t, subPath)
+
+	reopened, err := jdfs.OpenStore(store.Root)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, err = reopened.Index.Entry(jdex.MustParseACID("11.02+001"))
+	assert.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(store.Root, "10-19 Finance", "11 Clients", "11.01 Acme"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_OpenStore_BackfillsTimestampsInMemory(t *testing.T) {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	id := jdex.MustParseACID("11.01")
+	store.Index.PutArea(id, "Finance")
+	assert.NoError(t, store.PutCategory(id, "Clients"))
+	assert.NoError(t, os.MkdirAll(filepath.Join(store.Root, "10-19 Finance", "11 Clients", "11.01 Acme"), 0755))
+
+	// as an index from before timestamps were kept
+	indexPath, err := store.IndexPath()
+	assert.NoError(t, err)
+	index, err := os.ReadFile(indexPath)
+	assert.NoError(t, err)
+	index = append(index, "    11.01 Acme\n"...)
+	assert.NoError(t, os.WriteFile(indexPath, index, 0644))
+
+	reopened, err := jdfs.OpenStore(store.Root)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	entry, err := reopened.Index.Entry(id)
+	assert.NoError(t, err)
+	assert.False(t, entry.Created.IsZero())
+
+	unchanged, err := os.ReadFile(indexPath)
+	assert.NoError(t, err)
+	assert.Equal(t, string(index), string(unchanged))
+
+	assert.NoError(t, reopened.Save())
+	saved, err := os.ReadFile(indexPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(saved), "- Created: ")
+}
+
+func Test_OpenStoreReadOnly(t *testing.T) {
+	store := newUndoStore(t)
+	id := jdex.MustParseACID("11.01")
+
+	entryPath, err := store.EntryPath(id)
+	assert.NoError(t, err)
+
+	readOnly, err := jdfs.OpenStoreReadOnly(store.Root)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	located, err := readOnly.Locate(entryPath)
+	assert.NoError(t, err)
+	assert.Equal(t, id, located)
+
+	// nothing is written through it
+	logPath, err := store.AuditLogPath()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove(logPath))
+	assert.NoError(t, readOnly.Index.PutArea(id, "Money"))
+	assert.NoFileExists(t, logPath)
+}
+
+func Test_Store_ArchiveEntry(t *testing.T) {
+	store := newUndoStore(t)
+	archive := t.TempDir()
+	category := filepath.Join(store.Root, "10-19 Finance", "11 Clients")
+
+	_, err := store.ArchiveEntry(jdex.MustParseACID("11.01"), archive)
+	assert.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(category, "11.01 Acme"))
+	assert.DirExists(t, filepath.Join(archive, "10-19 Finance", "11 Clients", "11.01 Acme", "11.01+001 Invoice"))
+
+	reopened, err := jdfs.OpenStore(store.Root)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, err = reopened.Index.Entry(jdex.MustParseACID("11.01+001"))
+	assert.ErrorIs(t, err, jdex.ErrEntryNotFound)
+
+	_, err = store.ArchiveEntry(jdex.MustParseACID("00.00"), archive)
+	assert.ErrorIs(t, err, jdex.ErrProtectedID)
+}
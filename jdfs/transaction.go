@@ -0,0 +1,383 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// A batch of changes to a store, staged until it's committed.
+//
+// Changes to the index are made to Index, a snapshot of the store's, and
+// directories to make or move are queued with Mkdir and Move. Committing
+// makes them all together, keeping a journal so a crash part way through is
+// recovered from the next time the store is opened.
+type Transaction struct {
+	Index *jdex.Index // Where changes to the index are staged.
+
+	store *Store
+	view  *Store     // The store with the staged index, for working out paths.
+	base  string     // The store's index when the transaction began.
+	steps []planStep // Directories to make and move, in order.
+	done  bool
+}
+
+// A line of the transaction journal: the indexes on either side of the
+// transaction, a step about to be taken, or the mark that all were taken.
+type journalRecord struct {
+	planStep
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+const (
+	journalBegin   = "begin"
+	journalApplied = "applied"
+)
+
+const TransactionJournalFilename = "Transaction Journal.jsonl"
+
+var ErrTransactionConflict = errors.New("index was changed while the transaction was open")
+var ErrTransactionDone = errors.New("transaction was already committed or rolled back")
+
+// Start a transaction on the store.
+func (store *Store) Begin() (*Transaction, error) {
+	index := store.Index.Snapshot()
+	view := &Store{Root: store.Root, Index: index}
+
+	base, err := view.indexText()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		Index: index,
+		store: store,
+		view:  view,
+		base:  base,
+	}, nil
+}
+
+// Get the path to the transaction journal.
+func (store *Store) TransactionJournalPath() (string, error) {
+	return store.SystemFilePath(TransactionJournalFilename)
+}
+
+// Get the path to the directory of an area, category or entry, going by the
+// staged index.
+func (tx *Transaction) Path(id jdex.ACID) (string, error) {
+	return tx.view.Path(id)
+}
+
+// Get the path to the category directory, going by the staged index.
+func (tx *Transaction) CategoryPath(id jdex.ACID) (string, error) {
+	return tx.view.CategoryPath(id)
+}
+
+// Get the path to the entry directory, going by the staged index.
+func (tx *Transaction) EntryPath(id jdex.ACID) (string, error) {
+	return tx.view.EntryPath(id)
+}
+
+// Stage adding or updating an entry, queuing its directory to be made or
+// renamed to match.
+func (tx *Transaction) PutEntry(entry jdex.Entry) error {
+	oldPath, oldErr := tx.EntryPath(entry.ID)
+
+	err := tx.Index.PutEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	entryPath, err := tx.EntryPath(entry.ID)
+	if err != nil {
+		return err
+	}
+
+	if oldErr == nil && oldPath != entryPath && exists(oldPath) {
+		tx.Move(oldPath, entryPath)
+	}
+
+	tx.Mkdir(entryPath)
+	return nil
+}
+
+// Queue making a directory, along with any missing parents.
+func (tx *Transaction) Mkdir(dir string) {
+	tx.steps = append(tx.steps, planStep{Op: planStepMkdir, Path: dir})
+}
+
+// Queue moving a directory, making the destination's parents if missing.
+func (tx *Transaction) Move(from string, to string) {
+	tx.steps = append(tx.steps, planStep{Op: planStepMove, From: from, To: to})
+}
+
+// Throw away the staged changes.
+func (tx *Transaction) Rollback() {
+	tx.done = true
+}
+
+// Make the staged changes: the queued directories are made and moved, then
+// the staged index replaces the store's and is saved.
+//
+// Fails with ErrTransactionConflict if the store's index was changed since
+// the transaction began. Transactions on the same store commit one at a
+// time. If a directory can't be made or moved, those already done are put
+// back and the store is left as it was.
+func (tx *Transaction) Commit() (err error) {
+	if tx.done {
+		return ErrTransactionDone
+	}
+	tx.done = true
+
+	store := tx.store
+	store.commitMu.Lock()
+	defer store.commitMu.Unlock()
+
+	current, err := store.indexText()
+	if err != nil {
+		return
+	} else if current != tx.base {
+		return ErrTransactionConflict
+	}
+
+	after, err := tx.view.indexText()
+	if err != nil {
+		return
+	}
+
+	journalPath, err := store.TransactionJournalPath()
+	if err != nil {
+		return
+	}
+
+	journal, err := os.Create(journalPath)
+	if err != nil {
+		return
+	}
+	// kept when the store is left needing recovery
+	keepJournal := false
+	defer func() {
+		journal.Close()
+		if !keepJournal {
+			err = errors.Join(err, os.Remove(journalPath))
+		}
+	}()
+
+	write := func(record journalRecord) error {
+		if err := json.NewEncoder(journal).Encode(record); err != nil {
+			return err
+		}
+
+		return journal.Sync()
+	}
+
+	err = write(journalRecord{planStep: planStep{Op: journalBegin}, Before: tx.base, After: after})
+	if err != nil {
+		return
+	}
+
+	var taken []planStep
+	take := func(step planStep) error {
+		if err := write(journalRecord{planStep: store.relStep(step)}); err != nil {
+			return err
+		}
+
+		if err := takeStep(step); err != nil {
+			return err
+		}
+
+		taken = append(taken, step)
+		store.recordStep(step)
+		return nil
+	}
+
+	for _, step := range tx.steps {
+		err = tx.takeQueued(step, take)
+		if err != nil {
+			undoErr := undoSteps(taken)
+			keepJournal = undoErr != nil
+			return errors.Join(err, undoErr)
+		}
+	}
+
+	err = write(journalRecord{planStep: planStep{Op: journalApplied}})
+	if err != nil {
+		keepJournal = len(taken) > 0
+		return
+	}
+
+	store.Index.Replace(tx.Index)
+	err = store.Save()
+	keepJournal = err != nil
+	return
+}
+
+// Take a queued step, broken down into making each missing directory and
+// moving the one directory.
+func (tx *Transaction) takeQueued(step planStep, take func(planStep) error) error {
+	dir := step.Path
+	if step.Op == planStepMove {
+		dir = filepath.Dir(step.To)
+	}
+
+	var missing []string
+	for ; !exists(dir); dir = filepath.Dir(dir) {
+		missing = append(missing, dir)
+	}
+
+	for _, dir := range slices.Backward(missing) {
+		if err := take(planStep{Op: planStepMkdir, Path: dir}); err != nil {
+			return err
+		}
+	}
+
+	if step.Op == planStepMove {
+		return take(step)
+	}
+
+	return nil
+}
+
+func takeStep(step planStep) error {
+	switch step.Op {
+	case planStepMkdir:
+		return os.Mkdir(step.Path, 0755)
+	case planStepMove:
+		if exists(step.To) {
+			return fmt.Errorf("%q already exists", step.To)
+		}
+
+		return os.Rename(step.From, step.To)
+	}
+
+	return fmt.Errorf("unknown step %q", step.Op)
+}
+
+// Undo steps in reverse order, skipping those which didn't take effect.
+func undoSteps(steps []planStep) error {
+	var errs []error
+	for _, step := range slices.Backward(steps) {
+		switch step.Op {
+		case planStepMkdir:
+			if entries, err := os.ReadDir(step.Path); err == nil && len(entries) == 0 {
+				errs = append(errs, os.Remove(step.Path))
+			}
+		case planStepMove:
+			if exists(step.To) && !exists(step.From) {
+				errs = append(errs, os.Rename(step.To, step.From))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Finish or undo a transaction a crash left part way through. If all its
+// steps were taken, the index it staged is written. Otherwise, the steps are
+// undone and the index it started from is written back.
+func (store *Store) recoverTransaction() (err error) {
+	journalPath, err := store.TransactionJournalPath()
+	if err != nil {
+		return
+	}
+
+	journal, err := os.Open(journalPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return
+	}
+
+	var begin *journalRecord
+	var steps []planStep
+	applied := false
+
+	scanner := bufio.NewScanner(journal)
+	scanner.Buffer(nil, 1<<28)
+	for scanner.Scan() {
+		var record journalRecord
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			// a line cut short by the crash
+			break
+		}
+
+		switch record.Op {
+		case journalBegin:
+			begin = &record
+		case journalApplied:
+			applied = true
+		default:
+			steps = append(steps, store.absStep(record.planStep))
+		}
+	}
+	journal.Close()
+
+	if err = scanner.Err(); err != nil {
+		return
+	}
+
+	// without a beginning, nothing was changed
+	if begin != nil {
+		index := begin.After
+		if !applied {
+			if err = undoSteps(steps); err != nil {
+				return fmt.Errorf("failed to undo an unfinished transaction: %w", err)
+			}
+
+			index = begin.Before
+		}
+
+		var indexPath string
+		indexPath, err = store.IndexPath()
+		if err != nil {
+			return
+		}
+
+		err = os.WriteFile(indexPath, []byte(index), 0644)
+		if err != nil {
+			return
+		}
+	}
+
+	return os.Remove(journalPath)
+}
+
+// Make a step's paths relative to the store.
+func (store *Store) relStep(step planStep) planStep {
+	step.Path, step.From, step.To = store.relPath(step.Path), store.relPath(step.From), store.relPath(step.To)
+	return step
+}
+
+// Make a step's paths relative to the store absolute again.
+func (store *Store) absStep(step planStep) planStep {
+	for _, p := range []*string{&step.Path, &step.From, &step.To} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(store.Root, *p)
+		}
+	}
+
+	return step
+}
@@ -0,0 +1,233 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdex/jdexfile"
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Transaction_Commit(t *testing.T) {
+	store := newUndoStore(t)
+	category := filepath.Join(store.Root, "10-19 Finance", "11 Clients")
+
+	tx, err := store.Begin()
+	assert.NoError(t, err)
+
+	id := jdex.MustParseACID("11.02")
+	assert.NoError(t, tx.Index.PutEntry(jdex.Entry{ID: id, Name: "Globex"}))
+	entryPath, err := tx.EntryPath(id)
+	assert.NoError(t, err)
+	tx.Mkdir(filepath.Join(entryPath, "Invoices"))
+
+	// nothing happens until the transaction is committed
+	_, err = store.Index.Entry(id)
+	assert.Error(t, err)
+	assert.NoDirExists(t, entryPath)
+
+	assert.NoError(t, tx.Commit())
+	_, err = store.Index.Entry(id)
+	assert.NoError(t, err)
+	assert.DirExists(t, filepath.Join(category, "11.02 Globex", "Invoices"))
+	assert.ErrorIs(t, tx.Commit(), jdfs.ErrTransactionDone)
+
+	reopened, err := jdfs.OpenStore(store.Root)
+	assert.NoError(t, err)
+	_, err = reopened.Index.Entry(id)
+	assert.NoError(t, err)
+
+	journalPath, err := store.TransactionJournalPath()
+	assert.NoError(t, err)
+	assert.NoFileExists(t, journalPath)
+}
+
+func Test_Transaction_PutEntry(t *testing.T) {
+	store := newUndoStore(t)
+	category := filepath.Join(store.Root, "10-19 Finance", "11 Clients")
+
+	tx, err := store.Begin()
+	assert.NoError(t, err)
+
+	acme, _ := tx.Index.Entry(jdex.MustParseACID("11.01"))
+	acme.Name = "Acme Ltd"
+	acme.Tags = []string{"client"}
+	assert.NoError(t, tx.PutEntry(acme))
+	assert.NoError(t, tx.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.02"), Name: "Globex"}))
+	assert.NoDirExists(t, filepath.Join(category, "11.02 Globex"))
+
+	assert.NoError(t, tx.Commit())
+	assert.DirExists(t, filepath.Join(category, "11.01 Acme Ltd", "11.01+001 Invoice"))
+	assert.DirExists(t, filepath.Join(category, "11.02 Globex"))
+
+	entry, err := store.Index.Entry(acme.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"client"}, entry.Tags)
+}
+
+func Test_Transaction_Conflict(t *testing.T) {
+	store := newUndoStore(t)
+
+	tx, err := store.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.02"), Name: "Globex"}))
+
+	assert.NoError(t, store.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.03"), Name: "Initech"}))
+
+	assert.ErrorIs(t, tx.Commit(), jdfs.ErrTransactionConflict)
+	_, err = store.Index.Entry(jdex.MustParseACID("11.02"))
+	assert.Error(t, err)
+}
+
+func Test_Transaction_FailedStep(t *testing.T) {
+	store := newUndoStore(t)
+	category := filepath.Join(store.Root, "10-19 Finance", "11 Clients")
+
+	tx, err := store.Begin()
+	assert.NoError(t, err)
+
+	id := jdex.MustParseACID("11.02")
+	assert.NoError(t, tx.Index.PutEntry(jdex.Entry{ID: id, Name: "Globex"}))
+	tx.Mkdir(filepath.Join(category, "11.02 Globex"))
+	tx.Move(filepath.Join(category, "11.09 Missing"), filepath.Join(category, "11.02 Globex", "Missing"))
+
+	assert.Error(t, tx.Commit())
+	_, err = store.Index.Entry(id)
+	assert.Error(t, err)
+	assert.NoDirExists(t, filepath.Join(category, "11.02 Globex"))
+}
+
+func Test_Transaction_ConcurrentCommits(t *testing.T) {
+	store := newUndoStore(t)
+
+	var wg sync.WaitGroup
+	for n := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			id := jdex.MustParseACID(fmt.Sprintf("11.%02d", n+10))
+			for {
+				tx, err := store.Begin()
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				assert.NoError(t, tx.Index.PutEntry(jdex.Entry{ID: id, Name: "Concurrent"}))
+				entryPath, err := tx.EntryPath(id)
+				assert.NoError(t, err)
+				tx.Mkdir(entryPath)
+
+				err = tx.Commit()
+				if !errors.Is(err, jdfs.ErrTransactionConflict) {
+					assert.NoError(t, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	reopened, err := jdfs.OpenStore(store.Root)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for n := range 8 {
+		id := jdex.MustParseACID(fmt.Sprintf("11.%02d", n+10))
+		_, err := reopened.Index.Entry(id)
+		assert.NoError(t, err, id.String())
+	}
+}
+
+// Lay out a store as a crash part way through renaming 11.01 would leave it.
+func crashRename(t *testing.T, applied bool) *jdfs.Store {
+	store := newUndoStore(t)
+	category := filepath.Join(store.Root, "10-19 Finance", "11 Clients")
+
+	tx, err := store.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.01"), Name: "Globex"}))
+
+	var before, after bytes.Buffer
+	assert.NoError(t, jdexfile.Write(store.Index, &before))
+	assert.NoError(t, jdexfile.Write(tx.Index, &after))
+
+	journalPath, err := store.TransactionJournalPath()
+	assert.NoError(t, err)
+
+	var journal bytes.Buffer
+	encoder := json.NewEncoder(&journal)
+	encoder.Encode(map[string]string{"op": "begin", "before": before.String(), "after": after.String()})
+	encoder.Encode(map[string]string{
+		"op":   "move",
+		"from": filepath.Join("10-19 Finance", "11 Clients", "11.01 Acme"),
+		"to":   filepath.Join("10-19 Finance", "11 Clients", "11.01 Globex"),
+	})
+	if applied {
+		encoder.Encode(map[string]string{"op": "applied"})
+	}
+	assert.NoError(t, os.WriteFile(journalPath, journal.Bytes(), 0644))
+
+	assert.NoError(t, os.Rename(filepath.Join(category, "11.01 Acme"), filepath.Join(category, "11.01 Globex")))
+	return store
+}
+
+func Test_OpenStore_RollsBackTransaction(t *testing.T) {
+	store := crashRename(t, false)
+	category := filepath.Join(store.Root, "10-19 Finance", "11 Clients")
+
+	reopened, err := jdfs.OpenStore(store.Root)
+	assert.NoError(t, err)
+
+	entry, err := reopened.Index.Entry(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme", entry.Name)
+	assert.DirExists(t, filepath.Join(category, "11.01 Acme", "11.01+001 Invoice"))
+	assert.NoDirExists(t, filepath.Join(category, "11.01 Globex"))
+
+	journalPath, err := store.TransactionJournalPath()
+	assert.NoError(t, err)
+	assert.NoFileExists(t, journalPath)
+}
+
+func Test_OpenStore_RollsForwardTransaction(t *testing.T) {
+	store := crashRename(t, true)
+	category := filepath.Join(store.Root, "10-19 Finance", "11 Clients")
+
+	reopened, err := jdfs.OpenStore(store.Root)
+	assert.NoError(t, err)
+
+	entry, err := reopened.Index.Entry(jdex.MustParseACID("11.01"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Globex", entry.Name)
+	assert.DirExists(t, filepath.Join(category, "11.01 Globex", "11.01+001 Invoice"))
+
+	journalPath, err := store.TransactionJournalPath()
+	assert.NoError(t, err)
+	assert.NoFileExists(t, journalPath)
+}
@@ -0,0 +1,436 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdex/jdexfile"
+)
+
+// A change made to the store which can be undone, such as a renumbered entry.
+type Operation struct {
+	Description string     `json:"description"`     // What made the change, such as `mv 11.01 11.02`.
+	Time        time.Time  `json:"time"`            // When the change was made.
+	Index       indexPatch `json:"index"`           // Lines of the index which changed.
+	Steps       []planStep `json:"steps,omitempty"` // Directories made and moved, relative to the store.
+}
+
+// A change to the index, kept as the lines which changed rather than the whole
+// index, along with hashes of the whole index on either side of it.
+type indexPatch struct {
+	Line   int      `json:"line"`             // First line which changed, counting from 0.
+	Before []string `json:"before,omitempty"` // Lines there before the change.
+	After  []string `json:"after,omitempty"`  // Lines there after the change.
+
+	BeforeHash string `json:"beforeHash"`
+	AfterHash  string `json:"afterHash"`
+}
+
+type undoJournal struct {
+	Format int         `json:"format"`
+	Done   []Operation `json:"done"`   // Operations which can be undone, oldest first.
+	Undone []Operation `json:"undone"` // Operations which can be redone, most recently undone last.
+}
+
+const UndoJournalFilename = "Undo Journal.json"
+const UndoJournalFormat = 2
+
+// How many operations are kept to be undone.
+const UndoLimit = 50
+
+var ErrNothingToUndo = errors.New("nothing to undo")
+var ErrNothingToRedo = errors.New("nothing to redo")
+var ErrDrifted = errors.New("store has changed since")
+
+// Get the path to the undo journal.
+func (store *Store) UndoJournalPath() (string, error) {
+	return store.SystemFilePath(UndoJournalFilename)
+}
+
+// Run change as a single operation which can be undone, such as everything a
+// command does. The index and the directories the store makes or moves are
+// recorded, even if change fails part way through.
+func (store *Store) Record(description string, change func() error) (err error) {
+	before, err := store.indexText()
+	if err != nil {
+		return
+	}
+
+	operation := &Operation{Description: description, Time: time.Now()}
+	store.operation = operation
+	defer func() {
+		store.operation = nil
+	}()
+
+	changeErr := change()
+
+	after, err := store.indexText()
+	if err != nil {
+		return errors.Join(changeErr, err)
+	}
+
+	if after == before && len(operation.Steps) == 0 {
+		return changeErr
+	}
+
+	operation.Index = diffIndex(before, after)
+
+	journal, err := store.readUndoJournal()
+	if err == nil {
+		journal.Done = append(journal.Done, *operation)
+		journal.Done = journal.Done[max(len(journal.Done)-UndoLimit, 0):]
+		journal.Undone = nil
+		err = store.writeUndoJournal(journal)
+	}
+
+	return errors.Join(changeErr, err)
+}
+
+// Get the operations which can be undone, most recent first.
+func (store *Store) UndoHistory() ([]Operation, error) {
+	journal, err := store.readUndoJournal()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(journal.Done)
+	return journal.Done, nil
+}
+
+// Undo the last n operations, most recent first, returning the ones undone.
+//
+// Fails with ErrDrifted, leaving the operation in place, if the index or the
+// directories it touched were changed since in ways rzjd didn't record.
+func (store *Store) Undo(n int) (undone []Operation, err error) {
+	journal, err := store.readUndoJournal()
+	if err != nil {
+		return
+	}
+
+	for range n {
+		if len(journal.Done) == 0 {
+			if len(undone) == 0 {
+				err = ErrNothingToUndo
+			}
+			return
+		}
+
+		operation := journal.Done[len(journal.Done)-1]
+		err = store.revert(operation, true)
+		if err != nil {
+			return
+		}
+
+		journal.Done = journal.Done[:len(journal.Done)-1]
+		journal.Undone = append(journal.Undone, operation)
+		undone = append(undone, operation)
+
+		err = store.writeUndoJournal(journal)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Redo the last n operations undone, returning the ones redone.
+//
+// Like Undo, this fails with ErrDrifted if the store changed since.
+func (store *Store) Redo(n int) (redone []Operation, err error) {
+	journal, err := store.readUndoJournal()
+	if err != nil {
+		return
+	}
+
+	for range n {
+		if len(journal.Undone) == 0 {
+			if len(redone) == 0 {
+				err = ErrNothingToRedo
+			}
+			return
+		}
+
+		operation := journal.Undone[len(journal.Undone)-1]
+		err = store.revert(operation, false)
+		if err != nil {
+			return
+		}
+
+		journal.Undone = journal.Undone[:len(journal.Undone)-1]
+		journal.Done = append(journal.Done, operation)
+		redone = append(redone, operation)
+
+		err = store.writeUndoJournal(journal)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Take the store from one side of an operation to the other: back to before
+// it when undoing, or forward to after it otherwise.
+func (store *Store) revert(operation Operation, undo bool) (err error) {
+	current, err := store.indexText()
+	if err != nil {
+		return
+	}
+
+	to, ok := operation.Index.apply(current, undo)
+	if !ok {
+		return fmt.Errorf("%w: the index was changed after %q", ErrDrifted, operation.Description)
+	}
+
+	index, err := jdexfile.Read(strings.NewReader(to))
+	if err != nil {
+		return
+	}
+
+	err = store.replaySteps(operation.Steps, undo)
+	if err != nil {
+		return fmt.Errorf("%q: %w", operation.Description, err)
+	}
+
+	store.Index.Replace(index)
+	return store.Save()
+}
+
+// Take steps, or undo them in reverse order. If one can't be taken, those
+// already taken are put back.
+func (store *Store) replaySteps(steps []planStep, undo bool) (err error) {
+	ordered := slices.Clone(steps)
+	if undo {
+		slices.Reverse(ordered)
+	}
+
+	for n, step := range ordered {
+		err = store.replayStep(step, undo)
+		if err != nil {
+			for _, taken := range slices.Backward(ordered[:n]) {
+				err = errors.Join(err, store.replayStep(taken, !undo))
+			}
+
+			return
+		}
+	}
+
+	return
+}
+
+// Take a single step, or undo it, checking it'd leave things as they were.
+func (store *Store) replayStep(step planStep, undo bool) error {
+	switch step.Op {
+	case planStepMkdir:
+		dir := store.absStep(step).Path
+		if undo {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return fmt.Errorf("%w: %q is missing", ErrDrifted, dir)
+			} else if len(entries) > 0 {
+				return fmt.Errorf("%w: %q is no longer empty", ErrDrifted, dir)
+			}
+
+			if err = os.Remove(dir); err != nil {
+				return err
+			}
+
+			store.auditStep(planStep{Op: planStepRmdir, Path: dir})
+			return nil
+		}
+
+		if exists(dir) {
+			return fmt.Errorf("%w: %q already exists", ErrDrifted, dir)
+		}
+
+		if err := os.Mkdir(dir, 0755); err != nil {
+			return err
+		}
+
+		store.auditStep(planStep{Op: planStepMkdir, Path: dir})
+		return nil
+	case planStepRmdir:
+		return store.replayStep(planStep{Op: planStepMkdir, Path: step.Path}, !undo)
+	case planStepMove:
+		step = store.absStep(step)
+		from, to := step.From, step.To
+		if undo {
+			from, to = to, from
+		}
+
+		if !exists(from) {
+			return fmt.Errorf("%w: %q is missing", ErrDrifted, from)
+		}
+
+		if exists(to) {
+			return fmt.Errorf("%w: %q already exists", ErrDrifted, to)
+		}
+
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+
+		store.auditStep(planStep{Op: planStepMove, From: from, To: to})
+		return nil
+	}
+
+	return fmt.Errorf("unknown step %q", step.Op)
+}
+
+func exists(p string) bool {
+	_, err := os.Lstat(p)
+	return err == nil
+}
+
+// Write a step to the audit log, and to the operation being recorded.
+func (store *Store) recordStep(step planStep) {
+	store.auditStep(step)
+
+	if store.operation == nil {
+		return
+	}
+
+	store.operation.Steps = append(store.operation.Steps, store.relStep(step))
+}
+
+// Work out which lines of the index changed, going from before to after.
+func diffIndex(before string, after string) indexPatch {
+	a, b := strings.SplitAfter(before, "\n"), strings.SplitAfter(after, "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	return indexPatch{
+		Line:       prefix,
+		Before:     a[prefix : len(a)-suffix],
+		After:      b[prefix : len(b)-suffix],
+		BeforeHash: hashText(before),
+		AfterHash:  hashText(after),
+	}
+}
+
+// Take the index from one side of the patch to the other: back to before it
+// when undoing, or forward to after it otherwise. Fails if the index isn't
+// what the patch expects.
+func (patch indexPatch) apply(text string, undo bool) (string, bool) {
+	from, to, fromHash := patch.Before, patch.After, patch.BeforeHash
+	if undo {
+		from, to, fromHash = patch.After, patch.Before, patch.AfterHash
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if hashText(text) != fromHash || patch.Line+len(from) > len(lines) {
+		return "", false
+	}
+
+	return strings.Join(slices.Concat(lines[:patch.Line], to, lines[patch.Line+len(from):]), ""), true
+}
+
+func hashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func (store *Store) indexText() (string, error) {
+	var buffer bytes.Buffer
+	err := jdexfile.Write(store.Index, &buffer)
+	return buffer.String(), err
+}
+
+func (store *Store) readUndoJournal() (journal undoJournal, err error) {
+	journal.Format = UndoJournalFormat
+
+	journalPath, err := store.UndoJournalPath()
+	if err != nil {
+		return
+	}
+
+	data, err := os.ReadFile(journalPath)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+		return
+	} else if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &journal)
+	if err == nil && journal.Format == 1 {
+		err = upgradeUndoJournal(&journal, data)
+	}
+
+	if err == nil && journal.Format != UndoJournalFormat {
+		err = fmt.Errorf("%w: undo journal format %d", jdex.ErrUnknownFormat, journal.Format)
+	}
+
+	return
+}
+
+// Turn the whole indexes the first format kept on either side of each
+// operation into patches.
+func upgradeUndoJournal(journal *undoJournal, data []byte) error {
+	type indexes struct{ Before, After string }
+	var legacy struct{ Done, Undone []indexes }
+
+	err := json.Unmarshal(data, &legacy)
+	if err != nil {
+		return err
+	}
+
+	for n, old := range legacy.Done {
+		journal.Done[n].Index = diffIndex(old.Before, old.After)
+	}
+
+	for n, old := range legacy.Undone {
+		journal.Undone[n].Index = diffIndex(old.Before, old.After)
+	}
+
+	journal.Format = UndoJournalFormat
+	return nil
+}
+
+func (store *Store) writeUndoJournal(journal undoJournal) error {
+	journalPath, err := store.UndoJournalPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(journal)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(journalPath, data, 0644)
+}
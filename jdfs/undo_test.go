@@ -0,0 +1,185 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package jdfs_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUndoStore(t *testing.T) *jdfs.Store {
+	store, err := jdfs.NewStore(t.TempDir())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	id := jdex.MustParseACID("11.01")
+	store.Index.PutArea(id, "Finance")
+	assert.NoError(t, store.PutCategory(id, "Clients"))
+	assert.NoError(t, store.PutEntry(jdex.Entry{ID: id, Name: "Acme"}))
+	assert.NoError(t, store.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.01+001"), Name: "Invoice"}))
+
+	// as commands do, so the timestamps opening fills in aren't part of an
+	// operation
+	store, err = jdfs.OpenStore(store.Root)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return store
+}
+
+func Test_Store_UndoRedo(t *testing.T) {
+	store := newUndoStore(t)
+	category := filepath.Join(store.Root, "10-19 Finance", "11 Clients")
+
+	err := store.Record("mv 11.01 11.02", func() error {
+		_, err := store.RenumberEntry(jdex.MustParseACID("11.01"), jdex.MustParseACID("11.02"))
+		return err
+	})
+	assert.NoError(t, err)
+
+	err = store.Record("new 11 Globex", func() error {
+		return store.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.03"), Name: "Globex"})
+	})
+	assert.NoError(t, err)
+
+	// nothing changed, so nothing to undo
+	assert.NoError(t, store.Record("ls", func() error { return nil }))
+
+	history, err := store.UndoHistory()
+	assert.NoError(t, err)
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, "new 11 Globex", history[0].Description)
+	}
+
+	undone, err := store.Undo(2)
+	assert.NoError(t, err)
+	assert.Len(t, undone, 2)
+	assert.DirExists(t, filepath.Join(category, "11.01 Acme", "11.01+001 Invoice"))
+	assert.NoDirExists(t, filepath.Join(category, "11.02 Acme"))
+	assert.NoDirExists(t, filepath.Join(category, "11.03 Globex"))
+
+	_, err = store.Index.Entry(jdex.MustParseACID("11.01+001"))
+	assert.NoError(t, err)
+
+	_, err = store.Undo(1)
+	assert.ErrorIs(t, err, jdfs.ErrNothingToUndo)
+
+	// the undo survives reopening the store
+	reopened, err := jdfs.OpenStore(store.Root)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	redone, err := reopened.Redo(1)
+	assert.NoError(t, err)
+	assert.Len(t, redone, 1)
+	assert.DirExists(t, filepath.Join(category, "11.02 Acme", "11.02+001 Invoice"))
+
+	_, err = reopened.Index.Entry(jdex.MustParseACID("11.02+001"))
+	assert.NoError(t, err)
+}
+
+func Test_Store_UndoDrifted(t *testing.T) {
+	store := newUndoStore(t)
+	category := filepath.Join(store.Root, "10-19 Finance", "11 Clients")
+
+	err := store.Record("mv 11.01 11.02", func() error {
+		_, err := store.RenumberEntry(jdex.MustParseACID("11.01"), jdex.MustParseACID("11.02"))
+		return err
+	})
+	assert.NoError(t, err)
+
+	// something else took the old directory's place
+	assert.NoError(t, os.Mkdir(filepath.Join(category, "11.01 Acme"), 0755))
+
+	_, err = store.Undo(1)
+	assert.ErrorIs(t, err, jdfs.ErrDrifted)
+	assert.DirExists(t, filepath.Join(category, "11.02 Acme", "11.02+001 Invoice"))
+
+	_, err = store.Index.Entry(jdex.MustParseACID("11.02"))
+	assert.NoError(t, err)
+
+	// changing the index outside of an operation also counts
+	assert.NoError(t, os.Remove(filepath.Join(category, "11.01 Acme")))
+	assert.NoError(t, store.Index.PutArea(jdex.MustParseACID("21.01"), "Work"))
+
+	_, err = store.Undo(1)
+	assert.ErrorIs(t, err, jdfs.ErrDrifted)
+}
+
+func Test_Store_Record_KeepsChangedLines(t *testing.T) {
+	store := newUndoStore(t)
+
+	err := store.Record("new 11 Globex", func() error {
+		return store.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.03"), Name: "Globex"})
+	})
+	assert.NoError(t, err)
+
+	history, err := store.UndoHistory()
+	assert.NoError(t, err)
+	if assert.Len(t, history, 1) {
+		// the entry's line and its timestamps
+		assert.Empty(t, history[0].Index.Before)
+		if assert.Len(t, history[0].Index.After, 3) {
+			assert.Equal(t, "    11.03 Globex\n", history[0].Index.After[0])
+		}
+	}
+}
+
+func Test_Store_Undo_FirstJournalFormat(t *testing.T) {
+	store := newUndoStore(t)
+	indexPath, err := store.IndexPath()
+	assert.NoError(t, err)
+
+	before, err := os.ReadFile(indexPath)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Index.PutEntry(jdex.Entry{ID: jdex.MustParseACID("11.03"), Name: "Globex"}))
+	assert.NoError(t, store.Save())
+	after, err := os.ReadFile(indexPath)
+	assert.NoError(t, err)
+
+	journal, err := json.Marshal(map[string]any{
+		"format": 1,
+		"done": []map[string]any{{
+			"description": "new 11 Globex",
+			"time":        time.Now(),
+			"before":      string(before),
+			"after":       string(after),
+		}},
+	})
+	assert.NoError(t, err)
+
+	journalPath, err := store.UndoJournalPath()
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(journalPath, journal, 0644))
+
+	undone, err := store.Undo(1)
+	assert.NoError(t, err)
+	assert.Len(t, undone, 1)
+
+	_, err = store.Index.Entry(jdex.MustParseACID("11.03"))
+	assert.ErrorIs(t, err, jdex.ErrEntryNotFound)
+}
@@ -0,0 +1,209 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package rzconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/adrg/xdg"
+	"github.com/itisrazza/rzjd/jdex"
+	"github.com/itisrazza/rzjd/jdfs"
+)
+
+// Config is rzjd's user configuration, kept as JSON in the user's config
+// directory.
+type Config struct {
+	Profile  string             `json:"profile,omitempty"`  // Profile used when no other is picked.
+	Profiles map[string]Profile `json:"profiles,omitempty"` // Profiles by name.
+	Systems  map[string]string  `json:"systems,omitempty"`  // Store roots by system code, such as `W01`.
+}
+
+// Profile is a named store along with the settings to use with it.
+type Profile struct {
+	Store   string `json:"store"`             // Path to the store.
+	Editor  string `json:"editor,omitempty"`  // Text editor for notes.
+	Theme   string `json:"theme,omitempty"`   // Theme for interactive prompts.
+	Archive string `json:"archive,omitempty"` // Where archived entries are moved to.
+}
+
+// Name of the file which picks the profile for a directory and everything
+// below it.
+const ProfileMarkerFilename = ".rzjd-profile"
+
+var ErrInvalidSystemCode = errors.New("system code is expected to be a letter followed by two digits, such as W01")
+var ErrInvalidProfileName = errors.New("profile name is expected to be a single word")
+var ErrUnknownProfile = errors.New("profile does not exist")
+
+// Get the path to the config file. It can be moved with `RZJD_CONFIG`.
+func Path() string {
+	if configPath, ok := os.LookupEnv("RZJD_CONFIG"); ok {
+		return configPath
+	}
+
+	return path.Join(xdg.ConfigHome, "rzjd", "config.json")
+}
+
+// Read the config file, or an empty config if there isn't one yet.
+func Load() (config *Config, err error) {
+	config = &Config{}
+
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", Path(), err)
+	}
+
+	return
+}
+
+// Write the config back to the config file.
+func (config *Config) Save() error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	configPath := Path()
+	err = os.MkdirAll(path.Dir(configPath), 0755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(configPath, append(data, '\n'), 0644)
+}
+
+// Register the store root a system is kept in.
+func (config *Config) AddSystem(code string, root string) error {
+	if !jdex.IsSystemCode(code) {
+		return fmt.Errorf("%w: %q", ErrInvalidSystemCode, code)
+	}
+
+	if config.Systems == nil {
+		config.Systems = map[string]string{}
+	}
+
+	config.Systems[code] = root
+	return nil
+}
+
+// Forget about a system. Its store is left alone.
+func (config *Config) RemoveSystem(code string) error {
+	if _, ok := config.Systems[code]; !ok {
+		return fmt.Errorf("%w: %q", jdfs.ErrUnknownSystem, code)
+	}
+
+	delete(config.Systems, code)
+	return nil
+}
+
+// Add or replace a profile.
+func (config *Config) AddProfile(name string, profile Profile) error {
+	if name == "" || strings.ContainsFunc(name, unicode.IsSpace) {
+		return fmt.Errorf("%w: %q", ErrInvalidProfileName, name)
+	}
+
+	if config.Profiles == nil {
+		config.Profiles = map[string]Profile{}
+	}
+
+	config.Profiles[name] = profile
+	return nil
+}
+
+// Get a profile by name.
+func (config *Config) LookupProfile(name string) (Profile, error) {
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: %q", ErrUnknownProfile, name)
+	}
+
+	return profile, nil
+}
+
+// Make a profile the one used when no other is picked.
+func (config *Config) UseProfile(name string) error {
+	if _, err := config.LookupProfile(name); err != nil {
+		return err
+	}
+
+	config.Profile = name
+	return nil
+}
+
+// Pick the profile to use in a directory from the given flag, `RZJD_PROFILE`,
+// a marker in the directory or one of its parents, or the default, in that
+// order. An empty name means no profile was picked, and explicit is false
+// when the name is only the default.
+func (config *Config) PickProfile(flag *string, dir string) (name string, explicit bool, err error) {
+	if flag != nil {
+		return *flag, true, nil
+	}
+
+	if name, ok := os.LookupEnv("RZJD_PROFILE"); ok {
+		return name, true, nil
+	}
+
+	name, explicit, err = FindProfileMarker(dir)
+	if err != nil || explicit {
+		return
+	}
+
+	return config.Profile, false, nil
+}
+
+// Find the profile picked for a directory by a marker file in it or one of
+// its parents.
+func FindProfileMarker(dir string) (name string, ok bool, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+
+	for {
+		data, readErr := os.ReadFile(filepath.Join(dir, ProfileMarkerFilename))
+		if readErr == nil {
+			return strings.TrimSpace(string(data)), true, nil
+		} else if !errors.Is(readErr, os.ErrNotExist) {
+			return "", false, readErr
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false, nil
+		}
+
+		dir = parent
+	}
+}
+
+// Pick a profile for a directory and everything below it.
+func WriteProfileMarker(dir string, name string) error {
+	return os.WriteFile(filepath.Join(dir, ProfileMarkerFilename), []byte(name+"\n"), 0644)
+}
@@ -0,0 +1,104 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package rzconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itisrazza/rzjd/rzconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Config_AddProfile(t *testing.T) {
+	config := &rzconfig.Config{}
+
+	assert.NoError(t, config.AddProfile("work", rzconfig.Profile{Store: "/work"}))
+	assert.ErrorIs(t, config.AddProfile("", rzconfig.Profile{}), rzconfig.ErrInvalidProfileName)
+	assert.ErrorIs(t, config.AddProfile("my work", rzconfig.Profile{}), rzconfig.ErrInvalidProfileName)
+
+	profile, err := config.LookupProfile("work")
+	assert.NoError(t, err)
+	assert.Equal(t, "/work", profile.Store)
+
+	_, err = config.LookupProfile("home")
+	assert.ErrorIs(t, err, rzconfig.ErrUnknownProfile)
+}
+
+func Test_Config_UseProfile(t *testing.T) {
+	config := &rzconfig.Config{}
+	assert.NoError(t, config.AddProfile("work", rzconfig.Profile{Store: "/work"}))
+
+	assert.ErrorIs(t, config.UseProfile("home"), rzconfig.ErrUnknownProfile)
+	assert.Equal(t, "", config.Profile)
+
+	assert.NoError(t, config.UseProfile("work"))
+	assert.Equal(t, "work", config.Profile)
+}
+
+func Test_FindProfileMarker(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	assert.NoError(t, os.MkdirAll(nested, 0755))
+
+	_, ok, err := rzconfig.FindProfileMarker(nested)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, rzconfig.WriteProfileMarker(root, "work"))
+	name, ok, err := rzconfig.FindProfileMarker(nested)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "work", name)
+
+	assert.NoError(t, rzconfig.WriteProfileMarker(filepath.Join(root, "a"), "home"))
+	name, _, err = rzconfig.FindProfileMarker(nested)
+	assert.NoError(t, err)
+	assert.Equal(t, "home", name)
+}
+
+func Test_Config_PickProfile(t *testing.T) {
+	config := &rzconfig.Config{Profile: "default"}
+	dir := t.TempDir()
+	flag := "flag"
+
+	t.Setenv("RZJD_PROFILE", "")
+	os.Unsetenv("RZJD_PROFILE")
+
+	name, explicit, err := config.PickProfile(nil, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", name)
+	assert.False(t, explicit)
+
+	assert.NoError(t, rzconfig.WriteProfileMarker(dir, "marker"))
+	name, explicit, err = config.PickProfile(nil, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "marker", name)
+	assert.True(t, explicit)
+
+	t.Setenv("RZJD_PROFILE", "env")
+	name, explicit, err = config.PickProfile(nil, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "env", name)
+	assert.True(t, explicit)
+
+	name, explicit, err = config.PickProfile(&flag, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "flag", name)
+	assert.True(t, explicit)
+}
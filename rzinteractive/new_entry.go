@@ -0,0 +1,88 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package rzinteractive
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// Asks for the new entry or category's name, if it's missing, and a value for
+// each of the given fields. Answers are added to values.
+func NewEntryPrompt(id jdex.ACID, name *string, fields []jdex.Field, values map[string]string) error {
+	var inputs []huh.Field
+
+	if *name == "" {
+		inputs = append(inputs,
+			huh.NewInput().
+				Title("Name").
+				Description(fmt.Sprintf("Name of the new %s, %s.", id.Level(), id.String())).
+				Validate(func(s string) error {
+					if s == "" {
+						return errors.New("a name is required")
+					}
+					return nil
+				}).
+				Value(name),
+		)
+	}
+
+	answers := make([]string, len(fields))
+	for n, field := range fields {
+		if field.Default != nil {
+			answers[n] = field.Default.String()
+		}
+
+		if len(field.Allowed) > 0 {
+			inputs = append(inputs,
+				huh.NewSelect[string]().
+					Title(field.Key).
+					Options(huh.NewOptions(field.Allowed...)...).
+					Value(&answers[n]),
+			)
+			continue
+		}
+
+		inputs = append(inputs,
+			huh.NewInput().
+				Title(field.Key).
+				Description(fmt.Sprintf("A %s.", field.Type)).
+				Validate(func(s string) error {
+					_, err := jdex.NewValue(field.Type, s)
+					return err
+				}).
+				Value(&answers[n]),
+		)
+	}
+
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	if err := newForm(huh.NewGroup(inputs...)).Run(); err != nil {
+		return err
+	}
+
+	for n, field := range fields {
+		values[field.Key] = answers[n]
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package rzinteractive
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+	"github.com/itisrazza/rzjd/jdex"
+)
+
+// Lets the user pick which of the candidates some input was meant to refer
+// to.
+func PickCandidatePrompt(input string, candidates []jdex.Candidate) (id jdex.ACID, err error) {
+	options := make([]huh.Option[jdex.ACID], len(candidates))
+	for n, candidate := range candidates {
+		options[n] = huh.NewOption(fmt.Sprintf("%s %s", candidate.ID.String(), candidate.Name), candidate.ID)
+	}
+
+	form := newForm(
+		huh.NewGroup(
+			huh.NewSelect[jdex.ACID]().
+				Title(fmt.Sprintf("Which did you mean by \"%s\"?", input)).
+				Options(options...).
+				Value(&id),
+		),
+	)
+
+	err = form.Run()
+	return
+}
@@ -0,0 +1,105 @@
+// rzjd - Razza's Johnny.Decimal Management System
+// Copyright (C) 2025 Raresh Nistor
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package rzinteractive
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+	"github.com/itisrazza/rzjd/jdfs"
+)
+
+// Lets the user pick which of the proposed entries to move into the store.
+// Areas and categories left without entries are dropped from the plan.
+func ReviewPlanPrompt(plan *jdfs.Plan) error {
+	var options []huh.Option[int]
+	for n, item := range plan.Items {
+		if item.ID.Entry == "" {
+			continue
+		}
+
+		key := fmt.Sprintf("%s %s  <-  %s", item.ID.String(), item.Name, item.Path)
+		options = append(options, huh.NewOption(key, n).Selected(true))
+	}
+
+	selected := []int{}
+	confirm := false
+
+	form := newForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().
+				Title("Entries to create").
+				Description(fmt.Sprintf("Folders from \"%s\" which will be moved into the store.", plan.Source)).
+				Options(options...).
+				Value(&selected),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Reorganise these folders?").
+				Description("Use --write-plan to rename or renumber anything first.").
+				Value(&confirm),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return err
+	}
+
+	if !confirm {
+		return ErrCancel
+	}
+
+	keep := map[int]bool{}
+	for _, n := range selected {
+		keep[n] = true
+	}
+
+	plan.Items = pruneReviewedPlan(plan.Items, keep)
+	return nil
+}
+
+// Drops unselected entries, then any area or category which ends up empty.
+func pruneReviewedPlan(items []jdfs.PlanItem, keep map[int]bool) (pruned []jdfs.PlanItem) {
+	var area, category *jdfs.PlanItem
+	for n, item := range items {
+		switch {
+		case item.ID.Entry != "":
+			if !keep[n] {
+				continue
+			}
+
+			if area != nil {
+				pruned = append(pruned, *area)
+				area = nil
+			}
+
+			if category != nil {
+				pruned = append(pruned, *category)
+				category = nil
+			}
+
+			pruned = append(pruned, item)
+		case item.ID.Category != "":
+			category = &items[n]
+		default:
+			area = &items[n]
+			category = nil
+		}
+	}
+
+	return
+}
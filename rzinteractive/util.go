@@ -16,9 +16,45 @@
 
 package rzinteractive
 
-import "github.com/charmbracelet/huh"
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+var themes = map[string]func() *huh.Theme{
+	"base":       huh.ThemeBase,
+	"base16":     huh.ThemeBase16,
+	"catppuccin": huh.ThemeCatppuccin,
+	"charm":      huh.ThemeCharm,
+	"dracula":    huh.ThemeDracula,
+}
+
+var theme = huh.ThemeBase
+
+var ErrUnknownTheme = errors.New("unknown theme")
+
+// Get the names of the themes prompts can use.
+func Themes() []string {
+	return slices.Sorted(maps.Keys(themes))
+}
+
+// Set the theme used by prompts.
+func SetTheme(name string) error {
+	newTheme, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("%w: %q, expected one of %s", ErrUnknownTheme, name, strings.Join(Themes(), ", "))
+	}
+
+	theme = newTheme
+	return nil
+}
 
 func newForm(groups ...*huh.Group) *huh.Form {
 	return huh.NewForm(groups...).
-		WithTheme(huh.ThemeBase())
+		WithTheme(theme())
 }